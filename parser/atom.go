@@ -9,25 +9,33 @@ import (
 )
 
 type atomFeed struct {
-	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
-	ID      string      `xml:"id"`
-	Title   atomText    `xml:"title"`
-	Links   atomLinks   `xml:"link"`
-	Entries []atomEntry `xml:"entry"`
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Base    string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID      string       `xml:"id"`
+	Title   atomText     `xml:"title"`
+	Links   atomLinks    `xml:"link"`
+	Authors []atomPerson `xml:"author"`
+	Entries []atomEntry  `xml:"entry"`
 }
 
 type atomEntry struct {
-	ID        string    `xml:"id"`
-	Title     atomText  `xml:"title"`
-	Summary   atomText  `xml:"summary"`
-	Published string    `xml:"published"`
-	Updated   string    `xml:"updated"`
-	Links     atomLinks `xml:"link"`
-	Content   atomText  `xml:"http://www.w3.org/2005/Atom content"`
-	OrigLink  string    `xml:"http://rssnamespace.org/feedburner/ext/1.0 origLink"`
+	Base       string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID         string         `xml:"id"`
+	Title      atomText       `xml:"title"`
+	Summary    atomText       `xml:"summary"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Links      atomLinks      `xml:"link"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomText       `xml:"http://www.w3.org/2005/Atom content"`
+	OrigLink   string         `xml:"http://rssnamespace.org/feedburner/ext/1.0 origLink"`
+
+	mediaItem
 }
 
 type atomText struct {
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Type string `xml:"type,attr"`
 	Data string `xml:",chardata"`
 	XML  string `xml:",innerxml"`
@@ -36,10 +44,21 @@ type atomText struct {
 type atomLink struct {
 	Href string `xml:"href,attr"`
 	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
 }
 
 type atomLinks []atomLink
 
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
+}
+
 func (a *atomText) Text() string {
 	switch a.Type {
 	case "html":
@@ -67,6 +86,25 @@ func (links atomLinks) First(rel string) string {
 	return ""
 }
 
+func (links atomLinks) Enclosure(mediaType string) string {
+	for _, link := range links {
+		if link.Rel == "enclosure" && strings.HasPrefix(link.Type, mediaType) {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+func atomAuthors(persons []atomPerson) []Author {
+	var authors []Author
+	for _, person := range persons {
+		if name := strings.TrimSpace(person.Name); name != "" {
+			authors = append(authors, Author{Name: name, URL: strings.TrimSpace(person.URI)})
+		}
+	}
+	return authors
+}
+
 func ParseAtom(r io.Reader) (*Feed, error) {
 	var atom atomFeed
 	if err := utils.XMLDecoder(r).Decode(&atom); err != nil {
@@ -76,20 +114,45 @@ func ParseAtom(r io.Reader) (*Feed, error) {
 		Title:   atom.Title.String(),
 		SiteURL: cmp.Or(atom.Links.First("alternate"), atom.Links.First("")),
 	}
+	if feed.SiteURL != "" {
+		feed.SiteURL = resolveBase(atom.Base, feed.SiteURL)
+	}
 
+	feedAuthors := atomAuthors(atom.Authors)
 	for _, item := range atom.Entries {
+		categories := make([]string, len(item.Categories))
+		for i, category := range item.Categories {
+			categories[i] = cmp.Or(category.Term, category.Label)
+		}
+		authors := atomAuthors(item.Authors)
+		if authors == nil {
+			authors = feedAuthors
+		}
 		var linkFromID, guidFromID string
 		if strings.HasPrefix(item.ID, "http://") || strings.HasPrefix(item.ID, "https://") {
 			linkFromID = item.ID
 			guidFromID = item.ID + "::" + item.Updated
 		}
+		entryBase := resolveBase(atom.Base, item.Base)
 		link := cmp.Or(item.OrigLink, item.Links.First("alternate"), item.Links.First(""), linkFromID)
+		if link != "" {
+			link = resolveBase(entryBase, link)
+		}
+		content := item.Content
+		if content.String() == "" {
+			content = item.Summary
+		}
 		feed.Items = append(feed.Items, Item{
-			GUID:    cmp.Or(guidFromID, item.ID),
-			Date:    parseDate(cmp.Or(item.Published, item.Updated)),
-			URL:     link,
-			Title:   item.Title.Text(),
-			Content: cmp.Or(item.Content.String(), item.Summary.String()),
+			GUID:     cmp.Or(guidFromID, item.ID),
+			Date:     parseDate(cmp.Or(item.Published, item.Updated)),
+			URL:      link,
+			Title:    item.Title.Text(),
+			Authors:  authors,
+			Tags:     tags(categories),
+			Content:  content.String(),
+			ImageURL: cmp.Or(item.Image(), item.Links.Enclosure("image/")),
+			AudioURL: item.Links.Enclosure("audio/"),
+			base:     resolveBase(entryBase, content.Base),
 		})
 	}
 	return feed, nil
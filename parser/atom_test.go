@@ -47,6 +47,7 @@ func TestAtom(t *testing.T) {
 				Date:    new(time.Date(2003, 12, 13, 18, 30, 2, 0, time.UTC)),
 				URL:     "http://example.org/2003/12/13/atom03.html",
 				Title:   "Atom-Powered Robots Run Amok",
+				Authors: []Author{{Name: "John Doe"}},
 				Content: `<div xmlns="http://www.w3.org/1999/xhtml"><p>This is the entry content.</p></div>`,
 			},
 		},
@@ -193,3 +194,151 @@ func TestAtomDoesntEscapeHTMLTags(t *testing.T) {
 		t.Fatalf("want: %#v\nhave: %#v", want, have)
 	}
 }
+
+func TestAtomEnclosure(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom">
+			<entry>
+				<link rel="alternate" href="http://example.com/posts/1"/>
+				<link rel="enclosure" type="image/png" href="http://example.com/image.png"/>
+				<link rel="enclosure" type="audio/mpeg" href="http://example.com/audio.mp3" length="100500"/>
+			</entry>
+		</feed>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].AudioURL
+	want := "http://example.com/audio.mp3"
+	if want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestAtomYouTubeMediaGroup(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+			<entry>
+				<id>yt:video:abcdefghijk</id>
+				<title>Video</title>
+				<link rel="alternate" href="https://www.youtube.com/watch?v=abcdefghijk"/>
+				<media:group>
+					<media:title>Video</media:title>
+					<media:content url="https://www.youtube.com/v/abcdefghijk?version=3" type="application/x-shockwave-flash" width="640" height="390"/>
+					<media:thumbnail url="https://i2.ytimg.com/vi/abcdefghijk/hqdefault.jpg" width="480" height="360"/>
+					<media:description>Description</media:description>
+				</media:group>
+			</entry>
+		</feed>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].ImageURL
+	want := "https://i2.ytimg.com/vi/abcdefghijk/hqdefault.jpg"
+	if want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestAtomAuthors(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom">
+			<author><name>Feed Author</name></author>
+			<entry>
+				<author><name>Jane Doe</name><uri>https://example.com/jane</uri></author>
+				<author><name>John Doe</name></author>
+			</entry>
+			<entry></entry>
+		</feed>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := [][]Author{feed.Items[0].Authors, feed.Items[1].Authors}
+	want := [][]Author{
+		{{Name: "Jane Doe", URL: "https://example.com/jane"}, {Name: "John Doe"}},
+		{{Name: "Feed Author"}},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestAtomCategories(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom">
+			<entry>
+				<category term="go" label="Go"/>
+				<category label="Databases"/>
+			</entry>
+		</feed>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].Tags
+	want := []string{"go", "Databases"}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestAtomXMLBase(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom" xml:base="https://example.com/blog/">
+			<link href="/"/>
+			<entry xml:base="2024/">
+				<link href="post.html"/>
+				<content type="html" xml:base="https://cdn.example.com/post/">&lt;img src="a.png"&gt; &lt;a href="#top"&gt;top&lt;/a&gt;</content>
+			</entry>
+			<entry>
+				<link href="other.html"/>
+				<content type="html">&lt;a href="../about"&gt;about&lt;/a&gt;</content>
+			</entry>
+		</feed>
+	`), "https://example.com/atom.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := []string{feed.SiteURL}
+	for _, item := range feed.Items {
+		have = append(have, item.URL, item.Content)
+	}
+	want := []string{
+		"https://example.com/",
+		"https://example.com/blog/2024/post.html",
+		`<img src="https://cdn.example.com/post/a.png"> <a href="#top">top</a>`,
+		"https://example.com/blog/other.html",
+		`<a href="https://example.com/about">about</a>`,
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestAtomRelativeXMLBase(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<feed xmlns="http://www.w3.org/2005/Atom" xml:base="/blog/">
+			<link href="https://www.example.org/"/>
+			<entry>
+				<link href="https://www.example.org/post.html"/>
+				<content type="html">&lt;img src="a.png"&gt;</content>
+			</entry>
+		</feed>
+	`), "https://example.com/feeds/atom.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<img src="https://example.com/blog/a.png">`
+	have := feed.Items[0].Content
+	if want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// resolveBase resolves ref against an xml:base value, which may itself be
+// relative or empty.
+func resolveBase(base, ref string) string {
+	if base == "" {
+		return ref
+	}
+	baseUrl, err := url.Parse(strings.TrimSpace(base))
+	if err != nil {
+		return ref
+	}
+	refUrl, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return ref
+	}
+	return baseUrl.ResolveReference(refUrl).String()
+}
+
+var urlAttrs = map[string]struct{}{
+	"href":   {},
+	"src":    {},
+	"poster": {},
+	"cite":   {},
+}
+
+// resolveContentURLs rewrites relative URLs in HTML attributes to absolute
+// ones, leaving the rest of the markup untouched.
+func resolveContentURLs(content, base string) string {
+	baseUrl, err := url.Parse(base)
+	if err != nil || !baseUrl.IsAbs() || !strings.Contains(content, "<") {
+		return content
+	}
+
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			return b.String()
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			b.Write(tokenizer.Raw())
+			continue
+		}
+
+		raw := string(tokenizer.Raw())
+		token := tokenizer.Token()
+		changed := false
+		for i, attr := range token.Attr {
+			var val string
+			if _, ok := urlAttrs[attr.Key]; ok {
+				val = resolveURL(baseUrl, attr.Val)
+			} else if attr.Key == "srcset" {
+				val = resolveSrcset(baseUrl, attr.Val)
+			} else {
+				continue
+			}
+			if val != attr.Val {
+				token.Attr[i].Val = val
+				changed = true
+			}
+		}
+		if changed {
+			b.WriteString(token.String())
+		} else {
+			b.WriteString(raw)
+		}
+	}
+}
+
+func resolveURL(base *url.URL, href string) string {
+	trimmed := strings.TrimSpace(href)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return href
+	}
+	ref, err := url.Parse(trimmed)
+	if err != nil || ref.IsAbs() {
+		return href
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func resolveSrcset(base *url.URL, srcset string) string {
+	if strings.Contains(srcset, "data:") {
+		return srcset
+	}
+	srcs := strings.Split(srcset, ",")
+	for i, src := range srcs {
+		fields := strings.Fields(src)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = resolveURL(base, fields[0])
+		srcs[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(srcs, ", ")
+}
+
+// largestSrc returns the candidate of srcset with the largest width or
+// pixel density descriptor.
+func largestSrc(srcset string) string {
+	var src string
+	var max float64
+	for candidate := range strings.SplitSeq(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		size := 1.0
+		if len(fields) > 1 {
+			descriptor := fields[1]
+			if n, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64); err == nil {
+				size = n
+			}
+		}
+		if src == "" || size > max {
+			src, max = fields[0], size
+		}
+	}
+	return src
+}
+
+var backgroundImage = regexp.MustCompile(`(?i)background(?:-image)?\s*:[^;]*?url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+
+// styleImage returns the URL of the background image in a style attribute.
+func styleImage(style string) string {
+	if m := backgroundImage.FindStringSubmatch(style); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// renderContent renders n without the descendants matched by exclude, which
+// may be nil. Only the children of n are rendered if inner.
+func renderContent(n *html.Node, exclude selector, inner bool) (string, error) {
+	if exclude != nil {
+		n = cloneNode(n)
+		for _, m := range exclude.MatchAll(n) {
+			// skip n itself and attribute values selected by XPath
+			if m != n && m.Parent != nil {
+				m.Parent.RemoveChild(m)
+			}
+		}
+	}
+	var b strings.Builder
+	if !inner || n.Type != html.ElementNode && n.Type != html.DocumentNode {
+		if err := html.Render(&b, n); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	for c := range n.ChildNodes() {
+		if err := html.Render(&b, c); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// excludeContent removes the elements matched by exclude from an HTML
+// fragment.
+func excludeContent(content string, exclude selector) (string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), context)
+	if err != nil {
+		return "", err
+	}
+	root := &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	return renderContent(root, exclude, true)
+}
+
+// cloneNode returns a deep copy of n detached from its tree, so that matched
+// nodes of the original can still be extracted as other fields.
+func cloneNode(n *html.Node) *html.Node {
+	clone := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      slices.Clone(n.Attr),
+	}
+	for c := range n.ChildNodes() {
+		clone.AppendChild(cloneNode(c))
+	}
+	return clone
+}
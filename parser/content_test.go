@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestLargestSrc(t *testing.T) {
+	testcases := []struct {
+		srcset string
+		want   string
+	}{
+		{"a.png", "a.png"},
+		{"a.png 100w, b.png 800w, c.png 400w", "b.png"},
+		{"a.png 1x, b.png 2x", "b.png"},
+		{" a.png , b.png 1.5x", "b.png"},
+		{"", ""},
+	}
+	for _, testcase := range testcases {
+		if have := largestSrc(testcase.srcset); have != testcase.want {
+			t.Fatalf("%#v\nwant: %#v\nhave: %#v", testcase.srcset, testcase.want, have)
+		}
+	}
+}
+
+func TestStyleImage(t *testing.T) {
+	testcases := []struct {
+		style string
+		want  string
+	}{
+		{"background-image: url('/a.jpg')", "/a.jpg"},
+		{`color: red; background: #fff url("b.jpg") no-repeat`, "b.jpg"},
+		{"BACKGROUND-IMAGE:url( c.jpg )", "c.jpg"},
+		{"color: red", ""},
+	}
+	for _, testcase := range testcases {
+		if have := styleImage(testcase.style); have != testcase.want {
+			t.Fatalf("%#v\nwant: %#v\nhave: %#v", testcase.style, testcase.want, have)
+		}
+	}
+}
+
+func TestImageSource(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<img src=" a.png " data-srcset="b.png 1x, c.png 2x" style="background: url(d.png)">`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := doc.FirstChild.LastChild.FirstChild
+	testcases := []struct {
+		node *html.Node
+		key  string
+		want string
+	}{
+		{img, "src", "a.png"},
+		{img, "data-srcset", "c.png"},
+		{img, "style", "d.png"},
+		{img, "data-src", ""},
+		{nil, "src", ""},
+	}
+	for _, testcase := range testcases {
+		if have := imageSource(testcase.node, testcase.key); have != testcase.want {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.key, testcase.want, have)
+		}
+	}
+}
+
+func TestRenderContent(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<div class="post"><p>Text</p><span class="date">2024-01-02</span><a class="share">Share</a></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := doc.FirstChild.LastChild.FirstChild
+	css, err := compileSelector(".share, .date", "css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpath, err := compileSelector(".//a | .//@class", "xpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		exclude selector
+		inner   bool
+		want    string
+	}{
+		{nil, false, `<div class="post"><p>Text</p><span class="date">2024-01-02</span><a class="share">Share</a></div>`},
+		{nil, true, `<p>Text</p><span class="date">2024-01-02</span><a class="share">Share</a>`},
+		{css, false, `<div class="post"><p>Text</p></div>`},
+		{css, true, `<p>Text</p>`},
+		// attribute nodes are ignored
+		{xpath, true, `<p>Text</p><span class="date">2024-01-02</span>`},
+	}
+	for _, testcase := range testcases {
+		have, err := renderContent(post, testcase.exclude, testcase.inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != testcase.want {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.want, have)
+		}
+	}
+
+	// the original tree is left untouched
+	if have := css.MatchAll(post); len(have) != 2 {
+		t.Fatalf("want 2 excluded nodes left in the tree, have %d", len(have))
+	}
+}
+
+func TestExcludeContent(t *testing.T) {
+	exclude, err := compileSelector("script, .ad", "css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := excludeContent(`<p>One</p><div class="ad">Ad</div><script>track()</script><p>Two</p>`, exclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<p>One</p><p>Two</p>`
+	if have != want {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
@@ -12,6 +12,7 @@ type date struct {
 	hour, min, sec, msec int
 	timeZoneOffset       int // time zone offset in minutes
 	isLocal              bool
+	noYear               bool
 }
 
 func skip(s string, c byte) (string, bool) {
@@ -402,6 +403,7 @@ func parseDateOtherString(s string) (date, bool) {
 			d.month = nums[0]
 			d.day = nums[1]
 		} else if hasMon {
+			hasYear = true
 			d.year = nums[1]
 			if nums[1] < 100 {
 				d.year += 1900
@@ -424,17 +426,30 @@ func parseDateOtherString(s string) (date, bool) {
 		}
 		d.month = nums[0]
 		d.day = nums[1]
+		hasYear = true
 	default:
 		return date{}, false
 	}
+	d.noYear = !hasYear
 	return d, d.month > 0 && d.day > 0
 }
 
 func parseDate(date string) *time.Time {
+	return parseDateIn(date, time.Local)
+}
+
+// parseDateIn is like parseDate but interprets dates without time zone in loc.
+func parseDateIn(date string, loc *time.Location) *time.Time {
 	d, ok := parseDateISOString(date)
 	if !ok {
 		d, ok = parseDateOtherString(date)
 	}
+	if !ok {
+		if t := parseRelativeDate(date, loc); t != nil {
+			return t
+		}
+		d, ok = parseDateOtherString(normalizeDate(date))
+	}
 	if !ok {
 		return nil
 	}
@@ -447,15 +462,22 @@ func parseDate(date string) *time.Time {
 		(d.hour == 24 && (d.min != 0 || d.sec != 0 || d.msec != 0)) {
 		return nil
 	}
-	var loc *time.Location
-	if d.isLocal {
-		loc = time.Local
-	} else if d.timeZoneOffset == 0 {
-		loc = time.UTC
-	} else {
-		loc = time.FixedZone("", d.timeZoneOffset*60)
+	if !d.isLocal {
+		if d.timeZoneOffset == 0 {
+			loc = time.UTC
+		} else {
+			loc = time.FixedZone("", d.timeZoneOffset*60)
+		}
 	}
 	t := time.Date(d.year, time.Month(d.month), d.day, d.hour, d.min, d.sec, d.msec*1e6, loc)
+	if d.noYear {
+		// assume the latest year not in the future
+		now := time.Now().In(loc)
+		t = t.AddDate(now.Year()-t.Year(), 0, 0)
+		if t.After(now.AddDate(0, 0, 1)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+	}
 	unixMilli := t.UnixMilli()
 	const MAX_TIME = 8.64e15
 	if unixMilli >= -MAX_TIME && unixMilli <= MAX_TIME {
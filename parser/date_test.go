@@ -312,3 +312,105 @@ func TestDateParseNegative(t *testing.T) {
 		}
 	}
 }
+
+func TestDateParseFormat(t *testing.T) {
+	for _, testCase := range []struct {
+		date, format, timezone string
+		unix                   int64
+	}{
+		{"2024年3月5日 14:02", "2006年1月2日 15:04", "Asia/Shanghai", 1709618520000},
+		{"2024年3月5日 14:02", "%Y年%m月%d日 %H:%M", "+08:00", 1709618520000},
+		{"05.03.24", "%d.%m.%y", "UTC", 1709596800000},
+		{"March 5th, 2024", "%B %d, %Y", "UTC", 1709596800000},
+		{"2024-03-05 14:02", "", "Asia/Shanghai", 1709618520000},
+		{"2024-03-05T14:02:00Z", "", "Asia/Shanghai", 1709647320000},
+	} {
+		p, err := newDateParser(testCase.format, testCase.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		date := p.parse(testCase.date)
+		if date == nil {
+			t.Fatalf("fail to parse %#v with %#v", testCase.date, testCase.format)
+		}
+		if have := date.UnixMilli(); have != testCase.unix {
+			t.Fatalf("parse %#v: want %d, have %d", testCase.date, testCase.unix, have)
+		}
+	}
+}
+
+func TestDateParseLocalized(t *testing.T) {
+	want := time.Date(2024, 3, 5, 14, 2, 0, 0, time.Local).UnixMilli()
+	for _, testCase := range []string{
+		"5. März 2024, 14:02 Uhr",
+		"mardi 5 mars 2024 à 14h02",
+		"5 de marzo de 2024 14:02",
+		"5 marca 2024 14:02",
+		"5 марта 2024 г. 14:02",
+		"2024年3月5日 下午2:02",
+		"2024年03月05日 14时02分",
+		"2024年3月5日(火) 14:02",
+		"2024년 3월 5일 (화) 오후 2:02",
+	} {
+		date := parseDate(testCase)
+		if date == nil {
+			t.Fatalf("fail to parse %#v", testCase)
+		}
+		if have := date.UnixMilli(); have != want {
+			t.Fatalf("parse %#v: want %d, have %d", testCase, want, have)
+		}
+	}
+}
+
+func TestDateParseRelative(t *testing.T) {
+	now := time.Now()
+	for _, testCase := range []struct {
+		date string
+		ago  time.Duration
+	}{
+		{"just now", 0},
+		{"刚刚", 0},
+		{"3 hours ago", 3 * time.Hour},
+		{"an hour ago", time.Hour},
+		{"vor 5 Minuten", 5 * time.Minute},
+		{"il y a 2 heures", 2 * time.Hour},
+		{"hace 10 segundos", 10 * time.Second},
+		{"5 минут назад", 5 * time.Minute},
+		{"3小时前", 3 * time.Hour},
+		{"2 天前", 48 * time.Hour},
+		{"yesterday", 24 * time.Hour},
+		{"gestern", 24 * time.Hour},
+	} {
+		date := parseDate(testCase.date)
+		if date == nil {
+			t.Fatalf("fail to parse %#v", testCase.date)
+		}
+		// allow for DST changes and slow tests
+		if diff := now.Sub(*date) - testCase.ago; diff < -time.Hour || diff > time.Hour {
+			t.Fatalf("parse %#v: want %s ago, have %s", testCase.date, testCase.ago, now.Sub(*date))
+		}
+	}
+}
+
+func TestDateParseRelativeTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	feed := &Feed{Items: []Item{
+		{Date: parseDate("3 hours ago")},
+		{Date: parseDate("yesterday 14:30")},
+	}}
+	feed.SetDefaultTimezone(loc)
+
+	// relative dates are instants and not moved to the time zone of the feed
+	if ago := now.Sub(*feed.Items[0].Date); ago < 3*time.Hour-time.Minute || ago > 3*time.Hour+time.Minute {
+		t.Fatalf("want 3h ago, have %s ago", ago)
+	}
+	// but times of day are
+	have := *feed.Items[1].Date
+	if have.Location() != loc || have.Hour() != 14 || have.Minute() != 30 {
+		t.Fatalf("want 14:30 in %s, have %s", loc, have)
+	}
+}
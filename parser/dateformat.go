@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zones on systems without a database
+)
+
+// dateParser parses dates of scraped items.
+type dateParser struct {
+	layout string         // optional, heuristics are used if empty or not matching
+	loc    *time.Location // for dates without time zone
+}
+
+func newDateParser(format, timezone string) (*dateParser, error) {
+	layout, err := dateLayout(format)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	return &dateParser{layout, loc}, nil
+}
+
+var ordinalSuffix = regexp.MustCompile(`(\d)(?:st|nd|rd|th)\b`)
+
+func (p *dateParser) parse(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if p.layout != "" {
+		t, err := time.ParseInLocation(p.layout, ordinalSuffix.ReplaceAllString(s, "$1"), p.loc)
+		if err == nil {
+			if t.Year() == 0 {
+				// assume the latest year not in the future
+				now := time.Now().In(t.Location())
+				t = t.AddDate(now.Year(), 0, 0)
+				if t.After(now.AddDate(0, 0, 1)) {
+					t = t.AddDate(-1, 0, 0)
+				}
+			}
+			return &t
+		}
+	}
+	return parseDateIn(s, p.loc)
+}
+
+// LoadTimezone returns the location with the given IANA name or UTC offset
+// like +08:00. An empty name means the local time zone.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	if offset, s, ok := getTimeZoneOffset(name, false); ok && s == "" {
+		return time.FixedZone(name, offset*60), nil
+	}
+	return time.LoadLocation(name)
+}
+
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "1",
+	'b': "Jan",
+	'h': "Jan",
+	'B': "January",
+	'd': "2",
+	'e': "_2",
+	'j': "002",
+	'a': "Mon",
+	'A': "Monday",
+	'H': "15",
+	'I': "3",
+	'M': "4",
+	'S': "5",
+	'p': "PM",
+	'z': "-0700",
+	'Z': "MST",
+	'F': "2006-1-2",
+	'T': "15:4:5",
+	'R': "15:4",
+	'%': "%",
+}
+
+// dateLayout converts format to a Go layout. Formats containing % are
+// strftime-like, others are Go layouts already.
+func dateLayout(format string) (string, error) {
+	if !strings.Contains(format, "%") {
+		return format, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '-' {
+			// padding is optional when parsing anyway
+			i++
+		}
+		if i == len(format) {
+			return "", fmt.Errorf("incomplete directive in date format %q", format)
+		}
+		layout, ok := strftimeDirectives[format[i]]
+		if !ok {
+			return "", fmt.Errorf("unknown directive %%%c in date format %q", format[i], format)
+		}
+		b.WriteString(layout)
+	}
+	return b.String(), nil
+}
+
+// SetDefaultTimezone moves dates that were parsed without time zone to loc.
+func (feed *Feed) SetDefaultTimezone(loc *time.Location) {
+	for i := range feed.Items {
+		item := &feed.Items[i]
+		item.Date = inLocation(item.Date, loc)
+		item.DateModified = inLocation(item.DateModified, loc)
+	}
+}
+
+func inLocation(t *time.Time, loc *time.Location) *time.Time {
+	if t == nil || t.Location() != time.Local {
+		return t
+	}
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	moved := time.Date(year, month, day, hour, min, sec, t.Nanosecond(), loc)
+	return &moved
+}
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Month names of major European languages, replaced with English
+// abbreviations before parsing.
+var localMonthNames = [12][]string{
+	{"januar", "jänner", "janvier", "janv", "enero", "ene", "gennaio", "gen", "janeiro", "januari", "styczeń", "stycznia", "sty", "январь", "января", "янв"},
+	{"februar", "février", "févr", "fév", "febrero", "febbraio", "fevereiro", "fev", "februari", "luty", "lutego", "lut", "февраль", "февраля", "фев"},
+	{"märz", "mär", "mars", "marzo", "março", "maart", "mrt", "marzec", "marca", "март", "марта", "мар"},
+	{"avril", "avr", "abril", "abr", "aprile", "kwiecień", "kwietnia", "kwi", "апрель", "апреля", "апр"},
+	{"mai", "mayo", "maggio", "mag", "maio", "mei", "maj", "maja", "май", "мая"},
+	{"juin", "junio", "giugno", "giu", "junho", "czerwiec", "czerwca", "cze", "июнь", "июня", "июн"},
+	{"juillet", "juil", "julio", "luglio", "lug", "julho", "lipiec", "lipca", "lip", "июль", "июля", "июл"},
+	{"août", "aout", "agosto", "ago", "augustus", "augusti", "sierpień", "sierpnia", "sie", "август", "августа", "авг"},
+	{"septembre", "septiembre", "settembre", "set", "setembro", "wrzesień", "września", "wrz", "сентябрь", "сентября", "сен", "сент"},
+	{"oktober", "okt", "octobre", "octubre", "ottobre", "ott", "outubro", "out", "październik", "października", "paź", "октябрь", "октября", "окт"},
+	{"novembre", "noviembre", "novembro", "listopad", "listopada", "lis", "ноябрь", "ноября", "ноя"},
+	{"dezember", "dez", "décembre", "déc", "diciembre", "dic", "dicembre", "dezembro", "grudzień", "grudnia", "gru", "декабрь", "декабря", "дек"},
+}
+
+var localMonths = func() *regexp.Regexp {
+	var names []string
+	for _, month := range localMonthNames {
+		for _, name := range month {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	// longest names first
+	slices.SortFunc(names, func(a, b string) int { return len(b) - len(a) })
+	return regexp.MustCompile(`(?i)(^|[^\p{L}])(` + strings.Join(names, "|") + `)\.?([^\p{L}]|$)`)
+}()
+
+var (
+	cjkWeekday = regexp.MustCompile(`(星期|礼拜|禮拜|周|週)[一二三四五六日天]|[日月火水木金土]曜日?|[일월화수목금토]요일`)
+	cjkDate    = regexp.MustCompile(`(\d+)\s*([年년月월日일号號])`)
+	cjkTime    = regexp.MustCompile(`(\d+)\s*[时時시]\s*(?:(\d+)\s*[分분])?(?:\s*(\d+)\s*[秒초])?`)
+	// 14h30 in French
+	hourMinute = regexp.MustCompile(`(\d{1,2})\s?h\s?(\d{2})\b`)
+)
+
+// Words between date components that are ignored.
+var dateFillers = map[string]struct{}{
+	"at": {}, "de": {}, "del": {}, "di": {}, "du": {}, "do": {}, "da": {}, "à": {},
+	"um": {}, "uhr": {}, "om": {}, "kl": {}, "le": {}, "el": {}, "il": {},
+	"г": {}, "года": {}, "в": {},
+}
+
+// normalizeDate rewrites localized month names, CJK date markers and filler
+// words into a form understood by parseDateOtherString.
+func normalizeDate(s string) string {
+	s = localMonths.ReplaceAllStringFunc(s, func(m string) string {
+		sub := localMonths.FindStringSubmatch(m)
+		name := strings.ToLower(sub[2])
+		for i, month := range localMonthNames {
+			for _, n := range month {
+				if n == name {
+					return sub[1] + monthNamesLower[i] + sub[3]
+				}
+			}
+		}
+		return m
+	})
+
+	var suffix string
+	for _, marker := range []string{"下午", "午後", "오후"} {
+		if strings.Contains(s, marker) {
+			s = strings.ReplaceAll(s, marker, " ")
+			suffix = " pm"
+		}
+	}
+	for _, marker := range []string{"上午", "午前", "오전"} {
+		if strings.Contains(s, marker) {
+			s = strings.ReplaceAll(s, marker, " ")
+			suffix = " am"
+		}
+	}
+	s = cjkWeekday.ReplaceAllString(s, " ")
+	s = cjkDate.ReplaceAllStringFunc(s, func(m string) string {
+		sub := cjkDate.FindStringSubmatch(m)
+		switch sub[2] {
+		case "日", "일", "号", "號":
+			return sub[1] + " "
+		}
+		return sub[1] + "/"
+	})
+	s = cjkTime.ReplaceAllStringFunc(s, func(m string) string {
+		sub := cjkTime.FindStringSubmatch(m)
+		return sub[1] + ":" + cmp.Or(sub[2], "00") + ":" + cmp.Or(sub[3], "00") + " "
+	})
+	s = hourMinute.ReplaceAllString(s, "$1:$2")
+
+	fields := strings.Fields(s + suffix)
+	words := fields[:0]
+	for _, f := range fields {
+		if _, ok := dateFillers[strings.ToLower(strings.Trim(f, ".,"))]; !ok {
+			words = append(words, f)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Relative dates are evaluated against the current time.
+
+var relativeDays = map[string]int{
+	"today": 0, "heute": 0, "aujourd'hui": 0, "hoy": 0, "oggi": 0, "hoje": 0, "vandaag": 0, "dzisiaj": 0, "сегодня": 0, "今天": 0, "今日": 0, "오늘": 0,
+	"yesterday": 1, "gestern": 1, "hier": 1, "ayer": 1, "ieri": 1, "ontem": 1, "gisteren": 1, "wczoraj": 1, "вчера": 1, "昨天": 1, "昨日": 1, "어제": 1,
+	"vorgestern": 2, "avant-hier": 2, "anteayer": 2, "l'altro ieri": 2, "anteontem": 2, "eergisteren": 2, "przedwczoraj": 2, "позавчера": 2, "前天": 2, "一昨日": 2, "おととい": 2, "그저께": 2,
+}
+
+var relativeNow = map[string]struct{}{
+	"now": {}, "just now": {}, "right now": {}, "jetzt": {}, "gerade eben": {}, "soeben": {},
+	"maintenant": {}, "à l'instant": {}, "a l'instant": {}, "ahora": {}, "ahora mismo": {},
+	"adesso": {}, "ora": {}, "proprio ora": {}, "agora": {}, "agora mesmo": {}, "nu": {}, "zojuist": {},
+	"teraz": {}, "przed chwilą": {}, "сейчас": {}, "только что": {},
+	"刚刚": {}, "剛剛": {}, "刚才": {}, "剛才": {}, "たった今": {}, "今": {}, "방금": {}, "지금": {},
+}
+
+var (
+	relativeAgo = regexp.MustCompile(`(?i)^(?:(vor|il y a|hace|há|ha|przed)\s+)?(\d+|an?|one|eine[mnr]?|une?|uno|una|uma?|een|jeden|jedną)?\s*(\p{L}+?)\.?(?:\s*(ago|fa|geleden|temu|назад|前|以前|之前|전))?$`)
+	relativeDay = regexp.MustCompile(`(?i)^(.+?)(?:[\s,]*(?:at|um|à|a las|alle|às|om|o|в)?\s*(\d{1,2}):(\d{2})(?::(\d{2}))?)?$`)
+)
+
+// Units of relative dates, by prefix.
+var relativeUnits = []struct {
+	prefixes []string
+	duration time.Duration // zero for calendar units
+	days     int
+	months   int
+}{
+	{prefixes: []string{"sec", "seg", "sek", "сек", "秒", "초"}, duration: time.Second},
+	{prefixes: []string{"min", "мин", "分", "분"}, duration: time.Minute},
+	{prefixes: []string{"hour", "hr", "h", "stund", "heure", "hora", "ore", "ora", "uur", "godzin", "час", "小时", "小時", "时", "時", "시간"}, duration: time.Hour},
+	{prefixes: []string{"day", "tag", "jour", "día", "dia", "giorn", "dag", "dni", "dzień", "дн", "ден", "天", "日", "일"}, days: 1},
+	{prefixes: []string{"week", "wk", "woche", "semaine", "semana", "settiman", "tydz", "tygod", "недел", "周", "週", "星期", "주"}, days: 7},
+	{prefixes: []string{"month", "monat", "mois", "mes", "mês", "maand", "miesi", "месяц", "个月", "個月", "か月", "ヶ月", "カ月", "ヵ月", "月", "개월", "달"}, months: 1},
+	{prefixes: []string{"year", "yr", "jahr", "an", "año", "ano", "jaar", "rok", "lat", "год", "лет", "年", "년"}, months: 12},
+}
+
+// parseRelativeDate returns relative dates in UTC, as they are instants
+// rather than wall clock times in a time zone. Only dates with a time of day,
+// like "yesterday 14:30", are in loc.
+func parseRelativeDate(s string, loc *time.Location) *time.Time {
+	s = strings.ToLower(strings.TrimSpace(s))
+	now := time.Now().In(loc)
+	if _, ok := relativeNow[s]; ok {
+		now = now.UTC()
+		return &now
+	}
+
+	if m := relativeDay.FindStringSubmatch(s); m != nil {
+		if days, ok := relativeDays[m[1]]; ok {
+			t := now.AddDate(0, 0, -days)
+			if m[2] != "" {
+				hour, _ := strconv.Atoi(m[2])
+				min, _ := strconv.Atoi(m[3])
+				sec, _ := strconv.Atoi(m[4])
+				if hour > 23 || min > 59 || sec > 59 {
+					return nil
+				}
+				year, month, day := t.Date()
+				t = time.Date(year, month, day, hour, min, sec, 0, loc)
+				return &t
+			}
+			t = t.UTC()
+			return &t
+		}
+	}
+
+	m := relativeAgo.FindStringSubmatch(s)
+	if m == nil || m[1] == "" && m[4] == "" || m[1] != "" && m[4] != "" {
+		return nil
+	}
+	n := 1
+	if m[2] != "" {
+		if v, err := strconv.Atoi(m[2]); err == nil {
+			n = v
+		}
+	}
+	for _, unit := range relativeUnits {
+		for _, prefix := range unit.prefixes {
+			if !strings.HasPrefix(m[3], prefix) {
+				continue
+			}
+			var t time.Time
+			if unit.duration != 0 {
+				t = now.Add(-time.Duration(n) * unit.duration)
+			} else {
+				t = now.AddDate(0, -n*unit.months, -n*unit.days)
+			}
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxDiagnosticValues limits the number of values kept for each selector.
+	maxDiagnosticValues = 5
+	// maxDiagnosticValueLength limits the length of each value in bytes.
+	maxDiagnosticValueLength = 200
+)
+
+// Diagnostics collects what a rule does while it is applied, to help
+// debugging rules. The zero value is ready to use and a nil *Diagnostics
+// records nothing.
+type Diagnostics struct {
+	mu        sync.Mutex
+	Requests  []RequestDiagnostic  `json:"requests"`
+	Selectors []SelectorDiagnostic `json:"selectors"`
+	Console   []ConsoleMessage     `json:"console"`
+}
+
+type RequestDiagnostic struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Status   int               `json:"status,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Duration int64             `json:"duration_ms"` // until response headers
+	Error    string            `json:"error,omitempty"`
+}
+
+// SelectorDiagnostic counts matches of a selector or JSON path on a page.
+// Page is 0 for detail pages of items. Values holds the first few results of
+// JSON paths.
+type SelectorDiagnostic struct {
+	Page    int      `json:"page"`
+	Field   string   `json:"field"`
+	Expr    string   `json:"expr"`
+	Matches int      `json:"matches"`
+	Values  []string `json:"values,omitempty"`
+}
+
+type ConsoleMessage struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// client returns a copy of client recording requests made with it.
+func (d *Diagnostics) client(client *http.Client) *http.Client {
+	if d == nil {
+		return client
+	}
+	c := *client
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c.Transport = &diagnosticTransport{next, d}
+	return &c
+}
+
+type diagnosticTransport struct {
+	next http.RoundTripper
+	d    *Diagnostics
+}
+
+func (t *diagnosticTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	r := RequestDiagnostic{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Duration: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		r.Error = err.Error()
+	} else {
+		r.Status = resp.StatusCode
+		r.Headers = make(map[string]string, len(resp.Header))
+		for key := range resp.Header {
+			r.Headers[strings.ToLower(key)] = resp.Header.Get(key)
+		}
+	}
+	t.d.mu.Lock()
+	t.d.Requests = append(t.d.Requests, r)
+	t.d.mu.Unlock()
+	return resp, err
+}
+
+// match records the number of matches of field on page. Counts of repeated
+// calls for the same field and page add up.
+func (d *Diagnostics) match(page int, field, expr string, matches int, value string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := slices.IndexFunc(d.Selectors, func(s SelectorDiagnostic) bool {
+		return s.Page == page && s.Field == field
+	})
+	if i < 0 {
+		d.Selectors = append(d.Selectors, SelectorDiagnostic{Page: page, Field: field, Expr: expr})
+		i = len(d.Selectors) - 1
+	}
+	s := &d.Selectors[i]
+	s.Matches += matches
+	if matches > 0 && value != "" && len(s.Values) < maxDiagnosticValues {
+		if len(value) > maxDiagnosticValueLength {
+			value = strings.ToValidUTF8(value[:maxDiagnosticValueLength], "") + "…"
+		}
+		s.Values = append(s.Values, value)
+	}
+}
+
+// matchNode records a match of field if n is not nil.
+func (d *Diagnostics) matchNode(page int, field, expr string, n *html.Node) {
+	var matches int
+	if n != nil {
+		matches = 1
+	}
+	d.match(page, field, expr, matches, "")
+}
+
+// matchJSON records a match of field with its value if r exists.
+func (d *Diagnostics) matchJSON(page int, field, path string, r gjson.Result) {
+	var matches int
+	if r.Exists() {
+		matches = 1
+	}
+	d.match(page, field, path, matches, r.Raw)
+}
+
+func (d *Diagnostics) console(level, message string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	d.Console = append(d.Console, ConsoleMessage{level, message})
+	d.mu.Unlock()
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticsHTMLRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<li><a href="/1">One</a></li><li>Two</li>`)
+	}))
+	defer server.Close()
+
+	d := &Diagnostics{}
+	rule := &HTMLRule{URL: server.URL, Items: "li", ItemTitle: "li", ItemUrl: "a", Diagnostics: d}
+	if _, err := rule.Apply(server.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Requests) != 1 || d.Requests[0].Method != "GET" || d.Requests[0].URL != server.URL || d.Requests[0].Status != 200 {
+		t.Fatalf("have requests: %#v", d.Requests)
+	}
+	if ctype := d.Requests[0].Headers["content-type"]; ctype != "text/html" {
+		t.Fatalf("want text/html, have %#v", ctype)
+	}
+	want := []SelectorDiagnostic{
+		{Page: 1, Field: "title", Expr: "title"},
+		{Page: 1, Field: "items", Expr: "li", Matches: 2},
+		// counts of items add up
+		{Page: 1, Field: "item_title", Expr: "li", Matches: 2},
+		{Page: 1, Field: "item_url", Expr: "a", Matches: 1},
+	}
+	if !reflect.DeepEqual(want, d.Selectors) {
+		t.Fatalf("want: %#v\nhave: %#v", want, d.Selectors)
+	}
+}
+
+func TestDiagnosticsJSONRule(t *testing.T) {
+	long := strings.Repeat("é", maxDiagnosticValueLength)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"items": [{"title": "One"}, {"title": %q}, {}]}`, long)
+	}))
+	defer server.Close()
+
+	d := &Diagnostics{}
+	rule := &JSONRule{URL: server.URL, Items: "items", ItemTitle: "title", Diagnostics: d}
+	if _, err := rule.Apply(server.Client()); err != nil {
+		t.Fatal(err)
+	}
+	// values are truncated to valid UTF-8
+	truncated := strings.Repeat("é", (maxDiagnosticValueLength-1)/2) + "…"
+	want := []SelectorDiagnostic{
+		{Page: 1, Field: "items", Expr: "items", Matches: 3},
+		{Page: 1, Field: "item_title", Expr: "title", Matches: 2, Values: []string{`"One"`, `"` + truncated}},
+	}
+	if !reflect.DeepEqual(want, d.Selectors) {
+		t.Fatalf("want: %#v\nhave: %#v", want, d.Selectors)
+	}
+}
+
+func TestDiagnosticsConsole(t *testing.T) {
+	d := &Diagnostics{}
+	rule := &JavaScriptRule{Script: `
+		console.log('items', 2, {a: 1})
+		console.warn([1, 2])
+		console.error(undefined)
+	`, Diagnostics: d}
+	if _, err := rule.Apply(http.DefaultClient); err != nil {
+		t.Fatal(err)
+	}
+	want := []ConsoleMessage{
+		{"log", `items 2 {"a":1}`},
+		{"warn", "[1,2]"},
+		{"error", "undefined"},
+	}
+	if !reflect.DeepEqual(want, d.Console) {
+		t.Fatalf("want: %#v\nhave: %#v", want, d.Console)
+	}
+
+	// nil diagnostics record nothing
+	rule = &JavaScriptRule{Script: `console.log('ignored')`}
+	if _, err := rule.Apply(http.DefaultClient); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"cmp"
+	"log"
+	"net/http"
+	"rsslab/utils"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+const detailConcurrency = 4
+
+// DetailRule fetches the page of each item to extract fields missing from
+// the listing page.
+type DetailRule struct {
+	DetailContent      string `json:"detail_content"`
+	DetailDate         string `json:"detail_date_published"`
+	DetailDateAttr     string `json:"detail_date_published_attr"`
+	DetailImage        string `json:"detail_image"`
+	DetailImageAttr    string `json:"detail_image_attr"` // src or content of <meta> by default
+	DetailSelectorType string `json:"detail_selector_type"`
+
+	// Cache stores results by item GUID so that refreshes only fetch pages
+	// of new items. Optional.
+	Cache DetailCache `json:"-"`
+}
+
+type Detail struct {
+	Content  string
+	Date     *time.Time
+	ImageURL string
+}
+
+type DetailCache interface {
+	GetDetail(guid string) (*Detail, error)
+	SetDetail(guid string, detail *Detail) error
+}
+
+func (rule *DetailRule) enabled() bool {
+	return rule.DetailContent != "" || rule.DetailDate != "" || rule.DetailImage != ""
+}
+
+// applyDetails fills items with fields extracted from their pages.
+// Matches are recorded in d if not nil.
+func (rule *DetailRule) applyDetails(items []Item, headers map[string]string, charset string, client *http.Client, dates *dateParser, d *Diagnostics) error {
+	var contentSel, dateSel, imageSel selector
+	var err error
+	if rule.DetailContent != "" {
+		if contentSel, err = compileSelector(rule.DetailContent, rule.DetailSelectorType); err != nil {
+			return err
+		}
+	}
+	if rule.DetailDate != "" {
+		if dateSel, err = compileSelector(rule.DetailDate, rule.DetailSelectorType); err != nil {
+			return err
+		}
+	}
+	if rule.DetailImage != "" {
+		if imageSel, err = compileSelector(rule.DetailImage, rule.DetailSelectorType); err != nil {
+			return err
+		}
+	}
+
+	extract := func(url string) (*Detail, error) {
+		resp, err := tryRequest(url, headers, nil, client)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := DecodeBody(resp, charset)
+		if err != nil {
+			return nil, err
+		}
+		root, err := html.Parse(body)
+		if err != nil {
+			return nil, err
+		}
+
+		var detail Detail
+		if contentSel != nil {
+			content := contentSel.MatchFirst(root)
+			d.matchNode(0, "detail_content", rule.DetailContent, content)
+			if content != nil {
+				var b strings.Builder
+				if err := html.Render(&b, content); err != nil {
+					return nil, err
+				}
+				detail.Content = resolveContentURLs(b.String(), url)
+			}
+		}
+		if dateSel != nil {
+			date := dateSel.MatchFirst(root)
+			d.matchNode(0, "detail_date_published", rule.DetailDate, date)
+			if date != nil {
+				if rule.DetailDateAttr != "" {
+					detail.Date = dates.parse(nodeValue(date, rule.DetailDateAttr))
+				} else {
+					detail.Date = dates.parse(extractText(date))
+				}
+			}
+		}
+		if imageSel != nil {
+			image := imageSel.MatchFirst(root)
+			d.matchNode(0, "detail_image", rule.DetailImage, image)
+			var src string
+			if rule.DetailImageAttr != "" {
+				src = imageSource(image, rule.DetailImageAttr)
+			} else {
+				// <img src> or <meta property="og:image" content>
+				src = cmp.Or(imageSource(image, "src"), imageSource(image, "content"))
+			}
+			if src != "" {
+				detail.ImageURL = utils.AbsoluteUrl(src, url)
+			}
+		}
+		return &detail, nil
+	}
+
+	g := utils.NewGroup(detailConcurrency)
+	for i := range items {
+		item := &items[i]
+		if item.URL == "" {
+			continue
+		}
+		if rule.Cache != nil && item.GUID != "" {
+			detail, err := rule.Cache.GetDetail(item.GUID)
+			if err != nil {
+				return err
+			} else if detail != nil {
+				item.applyDetail(detail)
+				continue
+			}
+		}
+		g.Go(func() {
+			detail, err := extract(item.URL)
+			if err != nil {
+				log.Printf("%s: %s", item.URL, err)
+				return
+			}
+			item.applyDetail(detail)
+			if rule.Cache != nil && item.GUID != "" {
+				if err := rule.Cache.SetDetail(item.GUID, detail); err != nil {
+					log.Print(err)
+				}
+			}
+		})
+	}
+	g.Wait()
+	return nil
+}
+
+func (item *Item) applyDetail(detail *Detail) {
+	if detail.Content != "" {
+		item.Content = detail.Content
+	}
+	if detail.Date != nil {
+		item.Date = detail.Date
+	}
+	if detail.ImageURL != "" {
+		item.ImageURL = detail.ImageURL
+	}
+}
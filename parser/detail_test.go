@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryDetailCache struct {
+	mu      sync.Mutex
+	details map[string]*Detail
+}
+
+func (c *memoryDetailCache) GetDetail(guid string) (*Detail, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.details[guid], nil
+}
+
+func (c *memoryDetailCache) SetDetail(guid string, detail *Detail) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.details[guid] = detail
+	return nil
+}
+
+func TestDetailRule(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, `<ul><li><a href="/1">One</a></li><li><a href="/2">Two</a></li><li>Three</li></ul>`)
+			return
+		}
+		fmt.Fprintf(w, `
+			<article>Content %s <img srcset="small.png 1x, large.png 2x"></article>
+			<time datetime="2024-01-0%sT00:00:00Z"></time>
+		`, r.URL.Path[1:], r.URL.Path[1:])
+	}))
+	defer server.Close()
+
+	cached := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &memoryDetailCache{details: map[string]*Detail{
+		server.URL + "/2": {Content: "Cached", Date: &cached},
+	}}
+	rule := &HTMLRule{
+		URL:       server.URL,
+		Items:     "li",
+		ItemTitle: "li",
+		ItemUrl:   "a",
+		DetailRule: DetailRule{
+			DetailContent:   "article",
+			DetailDate:      "time",
+			DetailDateAttr:  "datetime",
+			DetailImage:     "img",
+			DetailImageAttr: "srcset",
+			Cache:           cache,
+		},
+	}
+	feed, err := rule.Apply(server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []Item{
+		{
+			GUID:     server.URL + "/1",
+			URL:      server.URL + "/1",
+			Title:    "One",
+			Content:  `<article>Content 1 <img srcset="` + server.URL + `/small.png 1x, ` + server.URL + `/large.png 2x"/></article>`,
+			Date:     &date,
+			ImageURL: server.URL + "/large.png",
+		},
+		{GUID: server.URL + "/2", URL: server.URL + "/2", Title: "Two", Content: "Cached", Date: &cached},
+		// no page to fetch
+		{GUID: feed.Items[2].GUID, Title: "Three", Content: "<li>Three</li>"},
+	}
+	if !reflect.DeepEqual(want, feed.Items) {
+		t.Fatalf("want: %#v\nhave: %#v", want, feed.Items)
+	}
+	if requests["/2"] != 0 {
+		t.Fatalf("cached page fetched %d times", requests["/2"])
+	}
+	if detail := cache.details[server.URL+"/1"]; detail == nil || detail.Content != want[0].Content {
+		t.Fatalf("detail not cached: %#v", detail)
+	}
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"io"
+	"mime"
+	"rsslab/utils"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type Link struct {
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+var alternateFormats = map[string]string{
+	"application/rss+xml":   "rss",
+	"application/rdf+xml":   "rdf",
+	"application/atom+xml":  "atom",
+	"application/feed+json": "json",
+}
+
+// Discover returns feeds advertised by <link rel="alternate"> elements of an HTML page.
+func Discover(r io.Reader, baseUrl string) ([]Link, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []Link
+	for n := range root.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		if n.DataAtom == atom.Base {
+			if href := attr(n, "href"); href != "" {
+				baseUrl = utils.AbsoluteUrl(href, baseUrl)
+			}
+			continue
+		}
+		if n.DataAtom != atom.Link {
+			continue
+		}
+		rel := strings.Fields(strings.ToLower(attr(n, "rel")))
+		if !slices.Contains(rel, "alternate") {
+			continue
+		}
+		mediaType, _, err := mime.ParseMediaType(attr(n, "type"))
+		if err != nil {
+			continue
+		}
+		format, ok := alternateFormats[mediaType]
+		if !ok {
+			continue
+		}
+		href := strings.TrimSpace(attr(n, "href"))
+		if href == "" {
+			continue
+		}
+		url := utils.AbsoluteUrl(href, baseUrl)
+		if url == "" || slices.ContainsFunc(links, func(l Link) bool { return l.URL == url }) {
+			continue
+		}
+		links = append(links, Link{
+			URL:    url,
+			Title:  utils.CollapseWhitespace(attr(n, "title")),
+			Format: format,
+		})
+	}
+	return links, nil
+}
+
+// Sniff returns the feed format of the given content, or an empty string
+// if the content is not a feed.
+func Sniff(lookup []byte) string {
+	format, _ := sniff(utils.BytesToString(lookup[:min(len(lookup), 2048)]))
+	return format
+}
+
+func attr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	have, err := Discover(strings.NewReader(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<title>Example</title>
+			<link rel="stylesheet" href="/style.css">
+			<link rel="alternate" type="application/rss+xml" title="Posts" href="/feed.xml">
+			<link rel="alternate" type="application/atom+xml; charset=utf-8" href="https://example.com/atom.xml">
+			<link rel="Alternate" type="application/feed+json" title="JSON  Feed" href="feed.json">
+			<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+			<link rel="alternate" hreflang="fr" href="/fr/">
+		</head>
+		<body></body>
+		</html>
+	`), "https://example.com/blog/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Link{
+		{URL: "https://example.com/feed.xml", Title: "Posts", Format: "rss"},
+		{URL: "https://example.com/atom.xml", Format: "atom"},
+		{URL: "https://example.com/blog/feed.json", Title: "JSON Feed", Format: "json"},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestDiscoverBaseElement(t *testing.T) {
+	have, err := Discover(strings.NewReader(`
+		<html><head>
+			<base href="https://cdn.example.com/site/">
+			<link rel="alternate" type="application/rss+xml" href="rss.xml">
+		</head></html>
+	`), "https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Link{{URL: "https://cdn.example.com/site/rss.xml", Format: "rss"}}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+
+	"golang.org/x/net/html"
+)
+
+var errEmbeddedNotFound = errors.New("embedded JSON not found")
+
+// embeddedJSON extracts JSON embedded in HTML pages, e.g. in
+// <script id="__NEXT_DATA__"> or application/ld+json blocks.
+type embeddedJSON struct {
+	selector string         // CSS selector of the element holding the JSON
+	regex    *regexp.Regexp // over the HTML, the first group is the JSON if any
+	sel      selector
+}
+
+func newEmbeddedJSON(sel, regex string) (*embeddedJSON, error) {
+	if sel == "" && regex == "" {
+		return nil, nil
+	}
+	e := embeddedJSON{selector: sel}
+	var err error
+	if sel != "" {
+		if e.sel, err = compileSelector(sel, "css"); err != nil {
+			return nil, err
+		}
+	}
+	if regex != "" {
+		if e.regex, err = regexp.Compile(regex); err != nil {
+			return nil, err
+		}
+	}
+	return &e, nil
+}
+
+// extract returns the embedded JSON of the HTML document b. The selector is
+// tried before the regular expression. Matches are recorded in d if not nil.
+func (e *embeddedJSON) extract(b []byte, page int, d *Diagnostics) ([]byte, error) {
+	if e.sel != nil {
+		root, err := html.Parse(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		if n := e.sel.MatchFirst(root); n == nil {
+			d.match(page, "embedded_selector", e.selector, 0, "")
+		} else {
+			j := extractText(n)
+			d.match(page, "embedded_selector", e.selector, 1, j)
+			return []byte(j), nil
+		}
+	}
+	if e.regex != nil {
+		m := e.regex.FindSubmatch(b)
+		if m == nil {
+			d.match(page, "embedded_regex", e.regex.String(), 0, "")
+		} else {
+			j := m[0]
+			if len(m) > 1 {
+				j = m[1]
+			}
+			d.match(page, "embedded_regex", e.regex.String(), 1, string(j))
+			return j, nil
+		}
+	}
+	return nil, errEmbeddedNotFound
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddedJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head>
+			<script type="application/ld+json">{"@type": "Organization"}</script>
+			<script id="__NEXT_DATA__" type="application/json">{"props": {"posts": [{"title": "Next & Nuxt"}]}}</script>
+			<script>window.__STATE__ = {"posts": [{"title": "State"}]};</script>
+		</head></html>`)
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		selector string
+		regex    string
+		items    string
+		want     []string
+		field    string
+	}{
+		{"#__NEXT_DATA__", "", "props.posts", []string{"Next & Nuxt"}, "embedded_selector"},
+		{"", `window.__STATE__ = (\{.*?\});`, "posts", []string{"State"}, "embedded_regex"},
+		// the regex is tried if no element matches
+		{"#__NUXT__", `window.__STATE__ = (\{.*?\});`, "posts", []string{"State"}, "embedded_regex"},
+	}
+	for _, testcase := range testcases {
+		d := &Diagnostics{}
+		rule := &JSONRule{
+			URL:              server.URL,
+			EmbeddedSelector: testcase.selector,
+			EmbeddedRegex:    testcase.regex,
+			Items:            testcase.items,
+			ItemTitle:        "title",
+			Diagnostics:      d,
+		}
+		feed, err := rule.Apply(server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have []string
+		for _, item := range feed.Items {
+			have = append(have, item.Title)
+		}
+		if !reflect.DeepEqual(testcase.want, have) {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.want, have)
+		}
+		var matched string
+		for _, s := range d.Selectors {
+			if s.Matches > 0 && (s.Field == "embedded_selector" || s.Field == "embedded_regex") {
+				matched = s.Field
+			}
+		}
+		if matched != testcase.field {
+			t.Fatalf("want match of %s, have %#v", testcase.field, d.Selectors)
+		}
+	}
+
+	rule := &JSONRule{URL: server.URL, EmbeddedSelector: "#__NUXT__", EmbeddedRegex: `__NUXT__ = (.*);`}
+	if _, err := rule.Apply(server.Client()); !errors.Is(err, errEmbeddedNotFound) {
+		t.Fatalf("want: %#v\nhave: %#v", errEmbeddedNotFound, err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/url"
 	"rsslab/utils"
+	"slices"
 	"strings"
 	"time"
 )
@@ -14,50 +15,103 @@ import (
 var ErrUnknownFormat = errors.New("unknown feed format")
 
 type Feed struct {
-	Title   string `json:"title,omitempty"`
-	SiteURL string `json:"home_page_url,omitempty"`
-	Items   []Item `json:"items,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	SiteURL     string   `json:"home_page_url,omitempty"`
+	FeedURL     string   `json:"feed_url,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Favicon     string   `json:"favicon,omitempty"`
+	Authors     []Author `json:"authors,omitempty"`
+	Items       []Item   `json:"items,omitempty"`
 }
 
 type Item struct {
-	GUID  string     `json:"id,omitempty"`
-	Date  *time.Time `json:"date_published,omitempty"`
-	URL   string     `json:"url,omitempty"`
-	Title string     `json:"title,omitempty"`
+	GUID         string     `json:"id,omitempty"`
+	Date         *time.Time `json:"date_published,omitempty"`
+	DateModified *time.Time `json:"date_modified,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	ExternalURL  string     `json:"external_url,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	Authors      []Author   `json:"authors,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
 
+	Summary  string `json:"summary,omitempty"`
 	Content  string `json:"content_html,omitempty"`
 	ImageURL string `json:"-"`
 	AudioURL string `json:"-"`
+
+	AudioDuration int    `json:"-"` // in seconds
+	ChaptersURL   string `json:"-"`
+	TranscriptURL string `json:"-"`
+
+	// base URL of relative references in content if it differs from URL,
+	// e.g. from xml:base
+	base string
+}
+
+type Author struct {
+	Name   string `json:"name,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+func (item *Item) Author() string {
+	names := make([]string, 0, len(item.Authors))
+	for _, author := range item.Authors {
+		if author.Name != "" {
+			names = append(names, author.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// tags trims and deduplicates categories, dropping empty ones.
+func tags(categories []string) []string {
+	var result []string
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category != "" && !slices.Contains(result, category) {
+			result = append(result, category)
+		}
+	}
+	return result
 }
 
 func Parse(r io.Reader, baseUrl string) (*Feed, error) {
+	feed, _, err := ParseFormat(r, baseUrl)
+	return feed, err
+}
+
+// ParseFormat is like Parse but also returns the detected feed format.
+func ParseFormat(r io.Reader, baseUrl string) (*Feed, string, error) {
 	lookup := make([]byte, 2048)
 	n, err := io.ReadFull(r, lookup)
 	if err == io.ErrUnexpectedEOF {
 		lookup = lookup[:n]
 		r = bytes.NewReader(lookup)
 	} else if err != nil {
-		return nil, err
+		return nil, "", err
 	} else {
 		r = io.MultiReader(bytes.NewReader(lookup), r)
 	}
 
-	_, parse := sniff(utils.BytesToString(lookup))
+	format, parse := sniff(utils.BytesToString(lookup))
 	if parse == nil {
-		return nil, ErrUnknownFormat
+		return nil, "", ErrUnknownFormat
 	}
 	feed, err := parse(r)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	base, err := url.Parse(baseUrl)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	siteUrl, err := url.Parse(feed.SiteURL)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	siteUrl = base.ResolveReference(siteUrl)
 	feed.SiteURL = siteUrl.String()
@@ -65,12 +119,26 @@ func Parse(r io.Reader, baseUrl string) (*Feed, error) {
 	for i := range feed.Items {
 		itemUrl, err := url.Parse(feed.Items[i].URL)
 		if err != nil {
-			return nil, err
+			return nil, "", err
+		}
+		item := &feed.Items[i]
+		item.URL = siteUrl.ResolveReference(itemUrl).String()
+		for _, u := range []*string{&item.ImageURL, &item.AudioURL, &item.ChaptersURL, &item.TranscriptURL} {
+			if *u != "" {
+				*u = utils.AbsoluteUrl(*u, item.URL)
+			}
+		}
+
+		contentBase := item.URL
+		if item.base != "" {
+			// a relative xml:base is relative to the document itself
+			contentBase = utils.AbsoluteUrl(item.base, base.String())
+			item.base = ""
 		}
-		feed.Items[i].URL = siteUrl.ResolveReference(itemUrl).String()
+		item.Content = resolveContentURLs(item.Content, contentBase)
 	}
 
-	return feed, nil
+	return feed, format, nil
 }
 
 func sniff(lookup string) (string, func(r io.Reader) (*Feed, error)) {
@@ -104,6 +172,8 @@ loop:
 					return "rss", ParseRSS
 				case "feed":
 					return "atom", ParseAtom
+				case "RDF":
+					return "rdf", ParseRDF
 				}
 			}
 		}
@@ -19,6 +19,10 @@ func TestSniff(t *testing.T) {
 			`<?xml version="1.0" encoding="utf-8"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
 			"atom",
 		},
+		{
+			`<?xml version="1.0"?><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"></rdf:RDF>`,
+			"rdf",
+		},
 		{
 			`{}`,
 			"json",
@@ -98,3 +102,26 @@ func TestParseFeedWithBOM(t *testing.T) {
 		t.Fatalf("want: %#v\nhave: %#v", want, have)
 	}
 }
+
+func TestParseFormat(t *testing.T) {
+	testcases := []struct {
+		input string
+		want  string
+	}{
+		{`<?xml version="1.0"?><rss version="2.0"><channel></channel></rss>`, "rss"},
+		{`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`, "atom"},
+		{`{"version": "https://jsonfeed.org/version/1.1", "items": []}`, "json"},
+	}
+	for _, testcase := range testcases {
+		_, have, err := ParseFormat(strings.NewReader(testcase.input), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != testcase.want {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.input, testcase.want, have)
+		}
+	}
+	if _, _, err := ParseFormat(strings.NewReader("<html></html>"), ""); err != ErrUnknownFormat {
+		t.Fatalf("want: %#v\nhave: %#v", ErrUnknownFormat, err)
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultGUIDHash lists the fields hashed into the GUID of scraped items
+// that have neither a GUID nor a URL. The date is left out as relative dates
+// change on every refresh.
+const defaultGUIDHash = "title,content"
+
+// guidFields parses a comma-separated list of item fields, where "none"
+// disables hashing.
+func guidFields(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		s = defaultGUIDHash
+	} else if s == "none" {
+		return nil, nil
+	}
+	var fields []string
+	for field := range strings.SplitSeq(s, ",") {
+		switch field = strings.TrimSpace(field); field {
+		case "title", "content", "date":
+			fields = append(fields, field)
+		default:
+			return nil, fmt.Errorf("unknown field %q in item_guid_hash", field)
+		}
+	}
+	return fields, nil
+}
+
+// setGUID sets the GUID of item to its URL or a hash of fields if it has
+// none.
+func (item *Item) setGUID(fields []string) {
+	if item.GUID != "" {
+		return
+	} else if item.URL != "" {
+		item.GUID = item.URL
+		return
+	} else if len(fields) == 0 {
+		return
+	}
+	h := sha1.New()
+	for _, field := range fields {
+		switch field {
+		case "title":
+			h.Write([]byte(item.Title))
+		case "content":
+			h.Write([]byte(item.Content))
+		case "date":
+			if item.Date != nil {
+				h.Write([]byte(item.Date.UTC().Format(time.RFC3339)))
+			}
+		}
+		h.Write([]byte{0})
+	}
+	item.GUID = "sha1:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGUIDFields(t *testing.T) {
+	testcases := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{"title", "content"}},
+		{"none", nil},
+		{"title", []string{"title"}},
+		{" title , date ", []string{"title", "date"}},
+	}
+	for _, testcase := range testcases {
+		have, err := guidFields(testcase.input)
+		if err != nil {
+			t.Fatalf("%#v: %s", testcase.input, err)
+		}
+		if !reflect.DeepEqual(testcase.want, have) {
+			t.Fatalf("%#v\nwant: %#v\nhave: %#v", testcase.input, testcase.want, have)
+		}
+	}
+	if _, err := guidFields("title,url"); err == nil {
+		t.Fatal("want error for unknown field")
+	}
+}
+
+func TestSetGUID(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hash := func(item Item, fields []string) string {
+		item.setGUID(fields)
+		return item.GUID
+	}
+	fields := []string{"title", "content", "date"}
+
+	if have := hash(Item{GUID: "id", URL: "https://example.com"}, fields); have != "id" {
+		t.Fatalf("want GUID, have %#v", have)
+	}
+	if have := hash(Item{URL: "https://example.com", Title: "Title"}, fields); have != "https://example.com" {
+		t.Fatalf("want URL, have %#v", have)
+	}
+	if have := hash(Item{Title: "Title"}, nil); have != "" {
+		t.Fatalf("want no GUID, have %#v", have)
+	}
+
+	item := Item{Title: "Title", Content: "Content", Date: &date}
+	have := hash(item, fields)
+	if !strings.HasPrefix(have, "sha1:") || have != hash(item, fields) {
+		t.Fatalf("want stable hash, have %#v", have)
+	}
+	// the same date in another time zone
+	local := date.In(time.FixedZone("", 8*60*60))
+	if other := hash(Item{Title: "Title", Content: "Content", Date: &local}, fields); other != have {
+		t.Fatalf("want %#v, have %#v", have, other)
+	}
+	for _, other := range []Item{
+		{Title: "Title 2", Content: "Content", Date: &date},
+		{Title: "Title", Content: "Content 2", Date: &date},
+		{Title: "Title", Content: "Content"},
+		// fields are separated
+		{Title: "TitleContent", Date: &date},
+	} {
+		if hash(other, fields) == have {
+			t.Fatalf("want different hash for %#v", other)
+		}
+	}
+	if hash(Item{Title: "Title", Content: "Content"}, []string{"title"}) != hash(Item{Title: "Title"}, []string{"title"}) {
+		t.Fatal("want hash of title only")
+	}
+}
+
+func TestGUIDStableAcrossRefreshes(t *testing.T) {
+	var refresh int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refresh++
+		// the relative date of the same event changes between refreshes
+		fmt.Fprintf(w, `
+			<div class="event"><h2>Concert</h2><p>Live music</p><span>%d hours ago</span></div>
+			<div class="event"><h2>Workshop</h2><p>Bring a laptop</p><span>yesterday</span></div>
+		`, refresh)
+	}))
+	defer server.Close()
+
+	rule := HTMLRule{URL: server.URL, Items: ".event", ItemTitle: "h2", ItemContent: "p", ItemDate: "span"}
+	var guids [2][]string
+	for i := range guids {
+		feed, err := rule.Apply(server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range feed.Items {
+			guids[i] = append(guids[i], item.GUID)
+		}
+	}
+	if len(guids[0]) != 2 || guids[0][0] == "" || guids[0][0] == guids[0][1] {
+		t.Fatalf("want 2 distinct GUIDs, have %#v", guids[0])
+	}
+	if !reflect.DeepEqual(guids[0], guids[1]) {
+		t.Fatalf("want: %#v\nhave: %#v", guids[0], guids[1])
+	}
+}
+
+func TestGUIDStableAcrossPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/json/") {
+			// an item moved to the next page
+			fmt.Fprint(w, `{"items": [{"title": "Event", "content": "<img src=\"event.png\">"}], "next": "../b/"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<div class="event"><h2>Event</h2><img src="event.png"></div><a class="next" href="../b/">Next</a>`)
+	}))
+	defer server.Close()
+
+	rules := []interface {
+		Apply(*http.Client) (*Feed, error)
+	}{
+		&HTMLRule{URL: server.URL + "/a/", Items: ".event", ItemTitle: "h2", NextPage: ".next", MaxPages: 2},
+		&JSONRule{URL: server.URL + "/json/a/", Items: "items", ItemTitle: "title", ItemContent: "content", NextPage: "next", MaxPages: 2},
+	}
+	for _, rule := range rules {
+		feed, err := rule.Apply(server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(feed.Items) != 2 || feed.Items[0].GUID != feed.Items[1].GUID {
+			t.Fatalf("want the same GUID on both pages, have %#v", feed.Items)
+		}
+		// content URLs are still resolved against each page
+		if feed.Items[0].Content == feed.Items[1].Content {
+			t.Fatalf("want content resolved against each page, have %#v", feed.Items)
+		}
+	}
+}
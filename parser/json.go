@@ -3,25 +3,60 @@ package parser
 import (
 	"cmp"
 	"encoding/json"
+	"fmt"
+	"html"
 	"io"
+	"slices"
+	"strings"
 )
 
+// Many JSON Feed 1.0 documents use http:// in the version URL.
+var jsonFeedVersionPrefixes = []string{"https://jsonfeed.org/version/", "http://jsonfeed.org/version/"}
+
+// https://www.jsonfeed.org/version/1.1/
 type jsonFeed struct {
-	Version string     `json:"version"`
-	Title   string     `json:"title"`
-	SiteURL string     `json:"home_page_url"`
-	Items   []jsonItem `json:"items"`
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	SiteURL     string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description"`
+	Icon        string     `json:"icon"`
+	Favicon     string     `json:"favicon"`
+	Authors     []Author   `json:"authors"`
+	Author      *Author    `json:"author"` // JSON Feed 1.0
+	Items       []jsonItem `json:"items"`
 }
 
 type jsonItem struct {
-	ID            string `json:"id"`
-	URL           string `json:"url"`
-	Title         string `json:"title"`
-	Summary       string `json:"summary"`
-	Text          string `json:"content_text"`
-	HTML          string `json:"content_html"`
-	DatePublished string `json:"date_published"`
-	DateModified  string `json:"date_modified"`
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	ExternalURL   string   `json:"external_url"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary"`
+	Text          string   `json:"content_text"`
+	HTML          string   `json:"content_html"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Authors       []Author `json:"authors"`
+	Author        *Author  `json:"author"` // JSON Feed 1.0
+	Tags          []string `json:"tags"`
+	Image         string   `json:"image"`
+	BannerImage   string   `json:"banner_image"`
+
+	Attachments []jsonAttachment `json:"attachments"`
+}
+
+type jsonAttachment struct {
+	URL      string  `json:"url"`
+	MIMEType string  `json:"mime_type"`
+	Duration float64 `json:"duration_in_seconds"`
+}
+
+func jsonAuthors(authors []Author, author *Author) []Author {
+	if len(authors) == 0 && author != nil {
+		authors = []Author{*author}
+	}
+	return authors
 }
 
 func ParseJSON(r io.Reader) (*Feed, error) {
@@ -29,18 +64,59 @@ func ParseJSON(r io.Reader) (*Feed, error) {
 	if err := json.NewDecoder(r).Decode(&jsonFeed); err != nil {
 		return nil, err
 	}
+	if jsonFeed.Version == "" {
+		return nil, fmt.Errorf("%w: JSON document has no JSON Feed version", ErrUnknownFormat)
+	} else if !slices.ContainsFunc(jsonFeedVersionPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(jsonFeed.Version, prefix)
+	}) {
+		return nil, fmt.Errorf("%w: unsupported JSON Feed version %q", ErrUnknownFormat, jsonFeed.Version)
+	}
 
 	feed := &Feed{
-		Title:   jsonFeed.Title,
-		SiteURL: jsonFeed.SiteURL,
+		Title:       jsonFeed.Title,
+		SiteURL:     jsonFeed.SiteURL,
+		FeedURL:     jsonFeed.FeedURL,
+		Description: jsonFeed.Description,
+		Icon:        jsonFeed.Icon,
+		Favicon:     jsonFeed.Favicon,
+		Authors:     jsonAuthors(jsonFeed.Authors, jsonFeed.Author),
 	}
 	for _, item := range jsonFeed.Items {
+		var podcastURL, imageURL string
+		var duration int
+		for _, a := range item.Attachments {
+			if podcastURL == "" && strings.HasPrefix(a.MIMEType, "audio/") {
+				podcastURL = a.URL
+				duration = int(a.Duration)
+			} else if imageURL == "" && strings.HasPrefix(a.MIMEType, "image/") {
+				imageURL = a.URL
+			}
+		}
+
+		authors := jsonAuthors(item.Authors, item.Author)
+		if authors == nil {
+			authors = feed.Authors
+		}
+
+		content := item.HTML
+		if content == "" && item.Text != "" {
+			content = html.EscapeString(item.Text)
+		}
+
 		feed.Items = append(feed.Items, Item{
-			GUID:    item.ID,
-			Date:    parseDate(cmp.Or(item.DatePublished, item.DateModified)),
-			URL:     item.URL,
-			Title:   item.Title,
-			Content: cmp.Or(item.HTML, item.Text, item.Summary),
+			GUID:          item.ID,
+			Date:          parseDate(cmp.Or(item.DatePublished, item.DateModified)),
+			DateModified:  parseDate(item.DateModified),
+			URL:           cmp.Or(item.URL, item.ExternalURL),
+			ExternalURL:   item.ExternalURL,
+			Title:         item.Title,
+			Authors:       authors,
+			Tags:          tags(item.Tags),
+			Summary:       item.Summary,
+			Content:       cmp.Or(content, html.EscapeString(item.Summary)),
+			ImageURL:      cmp.Or(item.Image, item.BannerImage, imageURL),
+			AudioURL:      podcastURL,
+			AudioDuration: duration,
 		})
 	}
 	return feed, nil
@@ -1,9 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestJSONFeed(t *testing.T) {
@@ -31,6 +33,7 @@ func TestJSONFeed(t *testing.T) {
 	want := &Feed{
 		Title:   "My Example Feed",
 		SiteURL: "https://example.org/",
+		FeedURL: "https://example.org/feed.json",
 		Items: []Item{
 			{GUID: "2", Content: "This is a second item.", URL: "https://example.org/second-item"},
 			{GUID: "1", Content: "<p>Hello, world!</p>", URL: "https://example.org/initial-post"},
@@ -40,3 +43,178 @@ func TestJSONFeed(t *testing.T) {
 		t.Fatalf("want: %#v\nhave: %#v", want, have)
 	}
 }
+
+func TestJSONFeedPodcast(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"items": [
+			{
+				"id": "1",
+				"attachments": [
+					{"url": "https://example.org/cover.jpg", "mime_type": "image/jpeg"},
+					{"url": "https://example.org/episode.m4a", "mime_type": "audio/x-m4a", "duration_in_seconds": 1800}
+				]
+			}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0]
+	if want := "https://example.org/episode.m4a"; want != have.AudioURL {
+		t.Fatalf("want: %#v\nhave: %#v", want, have.AudioURL)
+	}
+	if want := 1800; want != have.AudioDuration {
+		t.Fatalf("want: %#v\nhave: %#v", want, have.AudioDuration)
+	}
+}
+
+func TestJSONFeedImage(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"items": [
+			{"id": "1", "image": "https://example.org/image.png", "banner_image": "https://example.org/banner.png"},
+			{"id": "2", "banner_image": "https://example.org/banner.png"}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := "https://example.org/image.png", feed.Items[0].ImageURL; want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+	if want, have := "https://example.org/banner.png", feed.Items[1].ImageURL; want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestJSONFeedAuthors(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"authors": [{"name": "Feed Author"}],
+		"items": [
+			{"id": "1", "authors": [{"name": "Jane Doe", "url": "https://example.org/jane"}]},
+			{"id": "2"}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := [][]Author{feed.Items[0].Authors, feed.Items[1].Authors}
+	want := [][]Author{
+		{{Name: "Jane Doe", URL: "https://example.org/jane"}},
+		{{Name: "Feed Author"}},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestJSONFeedTags(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"items": [{"id": "1", "tags": ["go", "", "databases"]}]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].Tags
+	want := []string{"go", "databases"}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestJSONFeed11(t *testing.T) {
+	have, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Title",
+		"home_page_url": "https://example.org/",
+		"description": "Description",
+		"icon": "https://example.org/icon.png",
+		"favicon": "https://example.org/favicon.ico",
+		"items": [
+			{
+				"id": "1",
+				"external_url": "https://example.com/discussed",
+				"summary": "Summary",
+				"content_text": "1 < 2",
+				"date_published": "2024-03-05T14:02:00Z",
+				"date_modified": "2024-03-06T00:00:00Z"
+			}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Feed{
+		Title:       "Title",
+		SiteURL:     "https://example.org/",
+		Description: "Description",
+		Icon:        "https://example.org/icon.png",
+		Favicon:     "https://example.org/favicon.ico",
+		Items: []Item{
+			{
+				GUID:         "1",
+				Date:         new(time.Date(2024, 3, 5, 14, 2, 0, 0, time.UTC)),
+				DateModified: new(time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)),
+				URL:          "https://example.com/discussed",
+				ExternalURL:  "https://example.com/discussed",
+				Summary:      "Summary",
+				Content:      "1 &lt; 2",
+			},
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestJSONFeed10Author(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`{
+		"version": "https://jsonfeed.org/version/1",
+		"author": {"name": "Feed Author"},
+		"items": [
+			{"id": "1", "author": {"name": "Jane Doe", "avatar": "https://example.org/jane.png"}},
+			{"id": "2"}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := [][]Author{feed.Items[0].Authors, feed.Items[1].Authors}
+	want := [][]Author{
+		{{Name: "Jane Doe", Avatar: "https://example.org/jane.png"}},
+		{{Name: "Feed Author"}},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestJSONFeedVersion(t *testing.T) {
+	for _, input := range []string{
+		`{"data": [{"id": 1}]}`,
+		`{"version": "2.0", "items": []}`,
+	} {
+		_, err := Parse(strings.NewReader(input), "")
+		if !errors.Is(err, ErrUnknownFormat) {
+			t.Fatalf("input: %s\nwant: %#v\nhave: %#v", input, ErrUnknownFormat, err)
+		}
+	}
+}
+
+func TestJSONFeedVersionHTTP(t *testing.T) {
+	have, _ := Parse(strings.NewReader(`{
+		"version": "http://jsonfeed.org/version/1",
+		"title": "Title",
+		"items": [{"id": "1", "url": "https://example.com/1"}]
+	}`), "")
+	want := &Feed{
+		Title: "Title",
+		Items: []Item{{GUID: "1", URL: "https://example.com/1"}},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import "strings"
+
+// https://www.rssboard.org/media-rss
+type mediaGroup struct {
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Contents   []mediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+type mediaItem struct {
+	mediaGroup
+	Groups []mediaGroup `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+type mediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+type mediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	Medium     string           `xml:"medium,attr"`
+	Thumbnails []mediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+func (c *mediaContent) IsImage() bool {
+	return c.Medium == "image" || strings.HasPrefix(c.Type, "image/")
+}
+
+func (g *mediaGroup) Image() string {
+	for _, t := range g.Thumbnails {
+		if t.URL != "" {
+			return strings.TrimSpace(t.URL)
+		}
+	}
+	for _, c := range g.Contents {
+		for _, t := range c.Thumbnails {
+			if t.URL != "" {
+				return strings.TrimSpace(t.URL)
+			}
+		}
+	}
+	for _, c := range g.Contents {
+		if c.URL != "" && c.IsImage() {
+			return strings.TrimSpace(c.URL)
+		}
+	}
+	return ""
+}
+
+func (m *mediaItem) Image() string {
+	if image := m.mediaGroup.Image(); image != "" {
+		return image
+	}
+	for _, g := range m.Groups {
+		if image := g.Image(); image != "" {
+			return image
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"cmp"
+	"encoding/xml"
+	"io"
+	"rsslab/utils"
+	"strings"
+)
+
+const rdfNamespace = "http://purl.org/rss/1.0/"
+
+type rdfFeed struct {
+	XMLName xml.Name  `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Title   string    `xml:"http://purl.org/rss/1.0/ channel>title"`
+	Link    string    `xml:"http://purl.org/rss/1.0/ channel>link"`
+	Items   []rdfItem `xml:"http://purl.org/rss/1.0/ item"`
+}
+
+type rdfItem struct {
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string `xml:"http://purl.org/rss/1.0/ title"`
+	Link        string `xml:"http://purl.org/rss/1.0/ link"`
+	Description string `xml:"http://purl.org/rss/1.0/ description"`
+
+	DublinCoreDate     string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	DublinCoreCreators []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	DublinCoreSubjects []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	ContentEncoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+func ParseRDF(r io.Reader) (*Feed, error) {
+	var rdf rdfFeed
+	decoder := utils.XMLDecoder(r)
+	decoder.DefaultSpace = rdfNamespace
+	if err := decoder.Decode(&rdf); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{
+		Title:   strings.TrimSpace(rdf.Title),
+		SiteURL: strings.TrimSpace(rdf.Link),
+	}
+	for _, item := range rdf.Items {
+		feed.Items = append(feed.Items, Item{
+			GUID:    strings.TrimSpace(item.About),
+			Date:    parseDate(item.DublinCoreDate),
+			URL:     strings.TrimSpace(item.Link),
+			Title:   strings.TrimSpace(item.Title),
+			Authors: rssAuthors(item.DublinCoreCreators, ""),
+			Tags:    tags(item.DublinCoreSubjects),
+			Content: cmp.Or(strings.TrimSpace(item.ContentEncoded), strings.TrimSpace(item.Description)),
+		})
+	}
+	return feed, nil
+}
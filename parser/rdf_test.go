@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRDFFeed(t *testing.T) {
+	have, err := Parse(strings.NewReader(`
+		<?xml version="1.0"?>
+		<rdf:RDF
+			xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+			xmlns:dc="http://purl.org/dc/elements/1.1/"
+			xmlns="http://purl.org/rss/1.0/">
+			<channel rdf:about="http://www.xml.com/xml/news.rss">
+				<title>XML.com</title>
+				<link>http://xml.com/pub</link>
+				<description>XML.com features a rich mix of information and services for the XML community.</description>
+				<items>
+					<rdf:Seq>
+						<rdf:li resource="http://xml.com/pub/2000/08/09/xslt/xslt.html" />
+						<rdf:li resource="http://xml.com/pub/2000/08/09/rdfdb/index.html" />
+					</rdf:Seq>
+				</items>
+			</channel>
+			<item rdf:about="http://xml.com/pub/2000/08/09/xslt/xslt.html">
+				<title>Processing Inclusions with XSLT</title>
+				<link>http://xml.com/pub/2000/08/09/xslt/xslt.html</link>
+				<description>Processing document inclusions with general XML tools can be problematic.</description>
+				<dc:date>2000-08-09T10:00:00+01:00</dc:date>
+			</item>
+			<item rdf:about="http://xml.com/pub/2000/08/09/rdfdb/index.html">
+				<title>Putting RDF to Work</title>
+				<link>/pub/2000/08/09/rdfdb/index.html</link>
+				<description>Tool and API support for the Resource Description Framework is slowly coming of age.</description>
+			</item>
+		</rdf:RDF>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Feed{
+		Title:   "XML.com",
+		SiteURL: "http://xml.com/pub",
+		Items: []Item{
+			{
+				GUID:    "http://xml.com/pub/2000/08/09/xslt/xslt.html",
+				Date:    new(time.Date(2000, 8, 9, 10, 0, 0, 0, time.FixedZone("", 60*60))),
+				URL:     "http://xml.com/pub/2000/08/09/xslt/xslt.html",
+				Title:   "Processing Inclusions with XSLT",
+				Content: "Processing document inclusions with general XML tools can be problematic.",
+			},
+			{
+				GUID:    "http://xml.com/pub/2000/08/09/rdfdb/index.html",
+				URL:     "http://xml.com/pub/2000/08/09/rdfdb/index.html",
+				Title:   "Putting RDF to Work",
+				Content: "Tool and API support for the Resource Description Framework is slowly coming of age.",
+			},
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRDFContentEncoded(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<rdf:RDF
+			xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+			xmlns:content="http://purl.org/rss/1.0/modules/content/"
+			xmlns="http://purl.org/rss/1.0/">
+			<item>
+				<description>summary</description>
+				<content:encoded><![CDATA[<p>full content</p>]]></content:encoded>
+			</item>
+		</rdf:RDF>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].Content
+	want := "<p>full content</p>"
+	if want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRDFWithoutDefaultNamespace(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="utf-8"?>
+		<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+			<channel>
+				<title>Title</title>
+			</channel>
+			<item>
+				<title>Item 1</title>
+			</item>
+		</rdf:RDF>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Feed{
+		Title: "Title",
+		Items: []Item{{Title: "Item 1"}},
+	}
+	if !reflect.DeepEqual(want, feed) {
+		t.Fatalf("want: %#v\nhave: %#v", want, feed)
+	}
+}
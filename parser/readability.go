@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// A simplified port of Arc90's readability, as used by Miniflux:
+// https://github.com/miniflux/v2/blob/main/internal/reader/readability/readability.go
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
+	okMaybeCandidate   = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeClass      = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|modal|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget|byline|author|dateline|writtenby`)
+)
+
+var removedTags = map[atom.Atom]struct{}{
+	atom.Script:   {},
+	atom.Style:    {},
+	atom.Noscript: {},
+	atom.Link:     {},
+	atom.Form:     {},
+	atom.Button:   {},
+	atom.Input:    {},
+	atom.Select:   {},
+	atom.Textarea: {},
+	atom.Nav:      {},
+	atom.Aside:    {},
+	atom.Header:   {},
+	atom.Footer:   {},
+}
+
+// ExtractContent returns the main content of an HTML page, with relative
+// URLs resolved against baseUrl.
+func ExtractContent(r io.Reader, baseUrl string) (string, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+
+	var body *html.Node
+	var removed []*html.Node
+	for n := range root.Descendants() {
+		if n.Type == html.CommentNode {
+			removed = append(removed, n)
+			continue
+		} else if n.Type != html.ElementNode {
+			continue
+		}
+		if n.DataAtom == atom.Body {
+			body = n
+		}
+		if _, ok := removedTags[n.DataAtom]; ok {
+			removed = append(removed, n)
+			continue
+		}
+		if n.DataAtom == atom.Html || n.DataAtom == atom.Body || n.DataAtom == atom.Article {
+			continue
+		}
+		class := attr(n, "class") + " " + attr(n, "id")
+		if unlikelyCandidates.MatchString(class) && !okMaybeCandidate.MatchString(class) {
+			removed = append(removed, n)
+		}
+	}
+	for _, n := range removed {
+		if n.Parent != nil {
+			n.Parent.RemoveChild(n)
+		}
+	}
+	if body == nil {
+		return "", nil
+	}
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	for n := range body.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td:
+		case atom.Div:
+			// divs without block children are treated as paragraphs
+			if hasBlockChild(n) {
+				continue
+			}
+		default:
+			continue
+		}
+		text := strings.TrimSpace(extractText(n))
+		length := utf8.RuneCountInString(text)
+		if length < 25 {
+			continue
+		}
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+		score += math.Min(float64(length/100), 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+	}
+
+	var top *html.Node
+	for _, n := range candidates {
+		scores[n] *= 1 - linkDensity(n)
+		if top == nil || scores[n] > scores[top] {
+			top = n
+		}
+	}
+	if top == nil {
+		top = body
+	}
+
+	var b strings.Builder
+	b.WriteString("<div>")
+	threshold := math.Max(10, scores[top]*0.2)
+	siblings := []*html.Node{top}
+	if top.Parent != nil && top != body {
+		siblings = siblings[:0]
+		for n := top.Parent.FirstChild; n != nil; n = n.NextSibling {
+			siblings = append(siblings, n)
+		}
+	}
+	for _, n := range siblings {
+		include := n == top
+		if !include && n.Type == html.ElementNode {
+			if score, ok := scores[n]; ok && score >= threshold {
+				include = true
+			} else if n.DataAtom == atom.P {
+				text := extractText(n)
+				length := utf8.RuneCountInString(text)
+				density := linkDensity(n)
+				include = length > 80 && density < 0.25 ||
+					length > 0 && length <= 80 && density == 0 && strings.ContainsAny(text, ".。")
+			}
+		}
+		if include {
+			if err := html.Render(&b, n); err != nil {
+				return "", err
+			}
+		}
+	}
+	b.WriteString("</div>")
+	return resolveContentURLs(b.String(), baseUrl), nil
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Article:
+		score = 10
+	case atom.Div:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+	for _, key := range []string{"class", "id"} {
+		if val := attr(n, key); val != "" {
+			if negativeClass.MatchString(val) {
+				score -= 25
+			}
+			if positiveClass.MatchString(val) {
+				score += 25
+			}
+		}
+	}
+	return score
+}
+
+var blockTags = map[atom.Atom]struct{}{
+	atom.A:          {},
+	atom.Blockquote: {},
+	atom.Dl:         {},
+	atom.Div:        {},
+	atom.Img:        {},
+	atom.Ol:         {},
+	atom.P:          {},
+	atom.Pre:        {},
+	atom.Table:      {},
+	atom.Ul:         {},
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := range n.Descendants() {
+		if _, ok := blockTags[c.DataAtom]; ok && c.Type == html.ElementNode {
+			return true
+		}
+	}
+	return false
+}
+
+// linkDensity returns the ratio of link text to all text of n.
+func linkDensity(n *html.Node) float64 {
+	length := utf8.RuneCountInString(extractText(n))
+	if length == 0 {
+		return 0
+	}
+	var linkLength int
+	for c := range n.Descendants() {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linkLength += utf8.RuneCountInString(extractText(c))
+		}
+	}
+	return float64(linkLength) / float64(length)
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractContent(t *testing.T) {
+	have, err := ExtractContent(strings.NewReader(`
+		<html>
+		<head><title>Post</title><script>track()</script></head>
+		<body>
+			<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+			<div class="sidebar"><p>Subscribe to our newsletter, it is free, really, we promise.</p></div>
+			<div class="post-content">
+				<p>The first paragraph of the article, which is long enough to count, and has commas.</p>
+				<p>The second paragraph with an <img src="figure.png"> image, also long enough to count.</p>
+			</div>
+			<div class="comments"><p>Great post, thanks for sharing it with all of us here, cheers!</p></div>
+			<footer>Copyright</footer>
+		</body>
+		</html>
+	`), "https://example.com/posts/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div><div class="post-content">
+				<p>The first paragraph of the article, which is long enough to count, and has commas.</p>
+				<p>The second paragraph with an <img src="https://example.com/posts/figure.png"/> image, also long enough to count.</p>
+			</div></div>`
+	if have != want {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"path"
 	"rsslab/utils"
+	"strconv"
 	"strings"
 )
 
@@ -23,15 +24,25 @@ type rssItem struct {
 	Link        string         `xml:"rss link"`
 	Description string         `xml:"rss description"`
 	PubDate     string         `xml:"pubDate"`
+	Author      string         `xml:"rss author"`
+	Categories  []string       `xml:"rss category"`
 	Enclosures  []rssEnclosure `xml:"enclosure"`
 
-	DublinCoreDate string `xml:"http://purl.org/dc/elements/1.1/ date"`
-	ContentEncoded string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	DublinCoreDate     string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	DublinCoreCreators []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	ContentEncoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
 
 	OrigLink          string `xml:"http://rssnamespace.org/feedburner/ext/1.0 origLink"`
 	OrigEnclosureLink string `xml:"http://rssnamespace.org/feedburner/ext/1.0 origEnclosureLink"`
 
 	Torrent rssTorrent `xml:"torrent"`
+
+	mediaItem
+
+	ItunesDuration string           `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ItunesImage    rssHref          `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	Chapters       rssPodcastLink   `xml:"https://podcastindex.org/namespace/1.0 chapters"`
+	Transcripts    []rssPodcastLink `xml:"https://podcastindex.org/namespace/1.0 transcript"`
 }
 
 type rssGuid struct {
@@ -44,6 +55,15 @@ type rssEnclosure struct {
 	Type string `xml:"type,attr"`
 }
 
+type rssHref struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssPodcastLink struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+}
+
 type rssTorrent struct {
 	PubDate string `xml:"pubDate"`
 }
@@ -61,14 +81,15 @@ func ParseRSS(r io.Reader) (*Feed, error) {
 		SiteURL: strings.TrimSpace(rss.Link),
 	}
 	for _, item := range rss.Items {
-		var podcastURL string
+		var podcastURL, imageURL string
 		for _, e := range item.Enclosures {
-			if strings.HasPrefix(e.Type, "audio/") {
+			if podcastURL == "" && strings.HasPrefix(e.Type, "audio/") {
 				podcastURL = e.URL
 				if item.OrigEnclosureLink != "" && strings.Contains(podcastURL, path.Base(item.OrigEnclosureLink)) {
 					podcastURL = item.OrigEnclosureLink
 				}
-				break
+			} else if imageURL == "" && strings.HasPrefix(e.Type, "image/") {
+				imageURL = e.URL
 			}
 		}
 
@@ -77,14 +98,69 @@ func ParseRSS(r io.Reader) (*Feed, error) {
 			permalink = item.GUID.GUID
 		}
 
+		var transcriptURL string
+		if len(item.Transcripts) > 0 {
+			transcriptURL = item.Transcripts[0].URL
+		}
+
 		feed.Items = append(feed.Items, Item{
-			GUID:     strings.TrimSpace(item.GUID.GUID),
-			Date:     parseDate(cmp.Or(item.DublinCoreDate, item.PubDate, item.Torrent.PubDate)),
-			URL:      cmp.Or(item.OrigLink, item.Link, permalink),
-			Title:    strings.TrimSpace(item.Title),
-			Content:  cmp.Or(strings.TrimSpace(item.ContentEncoded), strings.TrimSpace(item.Description)),
-			AudioURL: podcastURL,
+			GUID:          strings.TrimSpace(item.GUID.GUID),
+			Date:          parseDate(cmp.Or(item.DublinCoreDate, item.PubDate, item.Torrent.PubDate)),
+			URL:           cmp.Or(item.OrigLink, item.Link, permalink),
+			Title:         strings.TrimSpace(item.Title),
+			Authors:       rssAuthors(item.DublinCoreCreators, item.Author),
+			Tags:          tags(item.Categories),
+			Content:       cmp.Or(strings.TrimSpace(item.ContentEncoded), strings.TrimSpace(item.Description)),
+			ImageURL:      cmp.Or(item.Image(), strings.TrimSpace(item.ItunesImage.Href), strings.TrimSpace(imageURL)),
+			AudioURL:      podcastURL,
+			AudioDuration: parseDuration(item.ItunesDuration),
+			ChaptersURL:   strings.TrimSpace(item.Chapters.URL),
+			TranscriptURL: strings.TrimSpace(transcriptURL),
 		})
 	}
 	return feed, nil
 }
+
+// rssAuthors prefers dc:creator, which holds plain names, over the RSS 2.0
+// author element, which is an email address optionally followed by a
+// parenthesized name, e.g. "lawyer@boyer.net (Lawyer Boyer)".
+func rssAuthors(creators []string, author string) []Author {
+	var authors []Author
+	for _, creator := range creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			authors = append(authors, Author{Name: creator})
+		}
+	}
+	if len(authors) > 0 {
+		return authors
+	}
+
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil
+	}
+	if i := strings.IndexByte(author, '('); i != -1 && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[i+1 : len(author)-1]); name != "" {
+			author = name
+		}
+	}
+	return []Author{{Name: author}}
+}
+
+// parseDuration parses itunes:duration, which is either a number of seconds
+// or a colon-separated HH:MM:SS / MM:SS value.
+func parseDuration(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	var seconds float64
+	for part := range strings.SplitSeq(s, ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return int(seconds)
+}
@@ -175,3 +175,211 @@ func TestRSSTorrentPubDate(t *testing.T) {
 		t.Fatalf("want: %#v\nhave: %#v", want, have)
 	}
 }
+
+func TestRSSPodcastMetadata(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0"
+			xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd"
+			xmlns:podcast="https://podcastindex.org/namespace/1.0">
+			<channel>
+				<item>
+					<enclosure length="100500" type="audio/mpeg" url="http://example.com/audio.mp3"/>
+					<itunes:duration>1:02:03</itunes:duration>
+					<itunes:image href="http://example.com/episode.jpg"/>
+					<podcast:chapters url="http://example.com/chapters.json" type="application/json+chapters"/>
+					<podcast:transcript url="http://example.com/transcript.vtt" type="text/vtt"/>
+					<podcast:transcript url="http://example.com/transcript.srt" type="application/srt"/>
+				</item>
+			</channel>
+		</rss>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items
+	want := []Item{
+		{
+			ImageURL:      "http://example.com/episode.jpg",
+			AudioURL:      "http://example.com/audio.mp3",
+			AudioDuration: 3723,
+			ChaptersURL:   "http://example.com/chapters.json",
+			TranscriptURL: "http://example.com/transcript.vtt",
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRSSPodcastRelativeURLs(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0">
+			<channel>
+				<link>https://example.com/podcast/</link>
+				<item>
+					<link>episodes/1</link>
+					<enclosure length="100500" type="audio/mpeg" url="/audio/1.mp3"/>
+					<podcast:chapters url="1/chapters.json" type="application/json+chapters"/>
+					<podcast:transcript url="1/transcript.vtt" type="text/vtt"/>
+				</item>
+			</channel>
+		</rss>
+	`), "https://example.com/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := feed.Items[0]
+	have := []string{item.AudioURL, item.ChaptersURL, item.TranscriptURL}
+	want := []string{
+		"https://example.com/audio/1.mp3",
+		"https://example.com/podcast/episodes/1/chapters.json",
+		"https://example.com/podcast/episodes/1/transcript.vtt",
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testcases := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"3600", 3600},
+		{"90.5", 90},
+		{"12:34", 754},
+		{"01:02:03", 3723},
+		{"abc", 0},
+	}
+	for _, testcase := range testcases {
+		want := testcase.want
+		have := parseDuration(testcase.input)
+		if want != have {
+			t.Fatalf("input: %s\nwant: %#v\nhave: %#v", testcase.input, want, have)
+		}
+	}
+}
+
+func TestRSSMediaThumbnail(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+			<channel>
+				<link>http://example.com/</link>
+				<item>
+					<media:content url="http://example.com/video.mp4" type="video/mp4">
+						<media:thumbnail url="http://example.com/video.jpg"/>
+					</media:content>
+				</item>
+				<item>
+					<media:content url="/photo.jpg" medium="image"/>
+				</item>
+				<item>
+					<enclosure type="image/png" url="http://example.com/enclosure.png"/>
+				</item>
+				<item>
+					<media:thumbnail url="http://example.com/thumbnail.jpg"/>
+					<media:content url="http://example.com/photo.jpg" medium="image"/>
+				</item>
+			</channel>
+		</rss>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have []string
+	for _, item := range feed.Items {
+		have = append(have, item.ImageURL)
+	}
+	want := []string{
+		"http://example.com/video.jpg",
+		"http://example.com/photo.jpg",
+		"http://example.com/enclosure.png",
+		"http://example.com/thumbnail.jpg",
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRSSAuthor(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+			<channel>
+				<item>
+					<author>lawyer@boyer.net (Lawyer Boyer)</author>
+				</item>
+				<item>
+					<author>lawyer@boyer.net</author>
+				</item>
+				<item>
+					<author>lawyer@boyer.net (Lawyer Boyer)</author>
+					<dc:creator>Jane Doe</dc:creator>
+					<dc:creator>John Doe</dc:creator>
+				</item>
+			</channel>
+		</rss>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have []string
+	for _, item := range feed.Items {
+		have = append(have, item.Author())
+	}
+	want := []string{"Lawyer Boyer", "lawyer@boyer.net", "Jane Doe, John Doe"}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRSSCategories(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0">
+			<channel>
+				<item>
+					<category>Go</category>
+					<category domain="http://example.com/tags"> Databases </category>
+					<category>Go</category>
+					<category></category>
+				</item>
+			</channel>
+		</rss>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].Tags
+	want := []string{"Go", "Databases"}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestRSSRelativeContentURLs(t *testing.T) {
+	feed, err := Parse(strings.NewReader(`
+		<?xml version="1.0" encoding="UTF-8"?>
+		<rss version="2.0">
+		<channel>
+			<link>https://example.com/</link>
+			<item>
+				<link>/posts/1/</link>
+				<description>&lt;p&gt;&lt;img src="cover.jpg" srcset="cover.jpg 1x, cover@2x.jpg 2x"&gt; &lt;a href="https://other.com/"&gt;x&lt;/a&gt;&lt;/p&gt;</description>
+			</item>
+		</channel>
+		</rss>
+	`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	have := feed.Items[0].Content
+	want := `<p><img src="https://example.com/posts/1/cover.jpg" srcset="https://example.com/posts/1/cover.jpg 1x, https://example.com/posts/1/cover@2x.jpg 2x"> <a href="https://other.com/">x</a></p>`
+	if have != want {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
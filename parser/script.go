@@ -0,0 +1,314 @@
+package parser
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"rsslab/utils"
+	"strings"
+	"time"
+
+	"github.com/buke/quickjs-go"
+	"golang.org/x/net/html"
+)
+
+// scriptPrelude wraps the host functions below with browser-like APIs.
+const scriptPrelude = `
+globalThis.fetch = (url, options = {}) => {
+	const res = JSON.parse(__fetch(String(url), JSON.stringify(options)))
+	return {
+		...res,
+		ok: res.status >= 200 && res.status < 300,
+		text: () => res.body,
+		json: () => JSON.parse(res.body),
+	}
+}
+
+class Element {
+	constructor(id) { this._id = id }
+	querySelector(selector) {
+		const id = __querySelector(this._id, String(selector))
+		return id < 0 ? null : new Element(id)
+	}
+	querySelectorAll(selector) {
+		return JSON.parse(__querySelectorAll(this._id, String(selector))).map(id => new Element(id))
+	}
+	getAttribute(name) { return __getAttribute(this._id, String(name)) }
+	get tagName() { return __node(this._id, 'tagName') }
+	get textContent() { return __node(this._id, 'textContent') }
+	get innerHTML() { return __node(this._id, 'innerHTML') }
+	get outerHTML() { return __node(this._id, 'outerHTML') }
+}
+
+globalThis.parseHTML = html => new Element(__parseHTML(String(html)))
+
+{
+	const format = args => args.map(arg => {
+		if (typeof arg === 'string') return arg
+		try {
+			return JSON.stringify(arg) ?? String(arg)
+		} catch {
+			return String(arg)
+		}
+	}).join(' ')
+	globalThis.console = Object.fromEntries(
+		['log', 'info', 'warn', 'error', 'debug'].map(level => [level, (...args) => __console(level, format(args))]),
+	)
+}
+`
+
+// storagePrelude backs the storage API with the host functions of
+// registerStorage. Values are stored as JSON.
+const storagePrelude = `
+globalThis.storage = {
+	get: key => {
+		const value = __storageGet(String(key))
+		return value === null ? null : JSON.parse(value)
+	},
+	set: (key, value) => __storageSet(String(key), value === undefined ? null : JSON.stringify(value)),
+}
+globalThis.lastRefreshed = __lastRefreshed === null ? null : new Date(__lastRefreshed)
+`
+
+// ScriptStorage persists values set by scripts through the storage API.
+type ScriptStorage interface {
+	LoadScriptValues() (map[string]string, error)
+	SaveScriptValues(values map[string]string) error
+}
+
+type scriptResponse struct {
+	URL     string            `json:"url"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+type scriptRequest struct {
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    *string           `json:"body"`
+}
+
+// registerHostFunctions exposes fetch(), parseHTML() and console to scripts.
+// Requests are canceled when reqCtx is done. Console output is recorded in d
+// if not nil.
+func registerHostFunctions(ctx *quickjs.Context, reqCtx context.Context, client *http.Client, d *Diagnostics) error {
+	globals := ctx.Globals()
+	globals.Set("__console", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) == 2 {
+			d.console(args[0].String(), args[1].String())
+		}
+		return ctx.NewUndefined()
+	}))
+	globals.Set("__fetch", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("fetch: missing arguments")
+		}
+		var options scriptRequest
+		if err := json.Unmarshal(utils.StringToBytes(args[1].String()), &options); err != nil {
+			return ctx.ThrowError(err)
+		}
+		res, err := scriptFetch(reqCtx, args[0].String(), &options, client)
+		if err != nil {
+			return ctx.ThrowError(err)
+		}
+		b, err := json.Marshal(res)
+		if err != nil {
+			return ctx.ThrowError(err)
+		}
+		return ctx.NewString(utils.BytesToString(b))
+	}))
+
+	var nodes []*html.Node
+	node := func(arg *quickjs.Value) *html.Node {
+		if id := int(arg.ToInt32()); id >= 0 && id < len(nodes) {
+			return nodes[id]
+		}
+		return nil
+	}
+	add := func(n *html.Node) int32 {
+		nodes = append(nodes, n)
+		return int32(len(nodes) - 1)
+	}
+	globals.Set("__parseHTML", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 1 {
+			return ctx.ThrowTypeError("parseHTML: missing argument")
+		}
+		root, err := html.Parse(strings.NewReader(args[0].String()))
+		if err != nil {
+			return ctx.ThrowError(err)
+		}
+		return ctx.NewInt32(add(root))
+	}))
+	globals.Set("__querySelector", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("querySelector: missing argument")
+		}
+		sel, err := compileSelector(args[1].String(), "css")
+		if err != nil {
+			return ctx.ThrowSyntaxError("%s", err)
+		}
+		n := node(args[0])
+		if n == nil {
+			return ctx.NewInt32(-1)
+		}
+		if m := sel.MatchFirst(n); m != nil {
+			return ctx.NewInt32(add(m))
+		}
+		return ctx.NewInt32(-1)
+	}))
+	globals.Set("__querySelectorAll", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("querySelectorAll: missing argument")
+		}
+		sel, err := compileSelector(args[1].String(), "css")
+		if err != nil {
+			return ctx.ThrowSyntaxError("%s", err)
+		}
+		ids := make([]int32, 0)
+		if n := node(args[0]); n != nil {
+			for _, m := range sel.MatchAll(n) {
+				ids = append(ids, add(m))
+			}
+		}
+		b, _ := json.Marshal(ids)
+		return ctx.NewString(utils.BytesToString(b))
+	}))
+	globals.Set("__getAttribute", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("getAttribute: missing argument")
+		}
+		if n := node(args[0]); n != nil {
+			key := args[1].String()
+			for _, attr := range n.Attr {
+				if attr.Key == key {
+					return ctx.NewString(attr.Val)
+				}
+			}
+		}
+		return ctx.NewNull()
+	}))
+	globals.Set("__node", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("missing argument")
+		}
+		n := node(args[0])
+		if n == nil {
+			return ctx.NewNull()
+		}
+		var b strings.Builder
+		switch args[1].String() {
+		case "tagName":
+			if n.Type != html.ElementNode {
+				return ctx.NewNull()
+			}
+			return ctx.NewString(strings.ToUpper(n.Data))
+		case "textContent":
+			return ctx.NewString(extractText(n))
+		case "innerHTML":
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if err := html.Render(&b, c); err != nil {
+					return ctx.ThrowError(err)
+				}
+			}
+		case "outerHTML":
+			if err := html.Render(&b, n); err != nil {
+				return ctx.ThrowError(err)
+			}
+		}
+		return ctx.NewString(b.String())
+	}))
+
+	ret := ctx.Eval(scriptPrelude)
+	defer ret.Free()
+	if ret.IsException() {
+		return ctx.Exception()
+	}
+	return nil
+}
+
+// registerStorage exposes values as the storage API and lastRefreshed to
+// scripts. It reports whether values have been modified.
+func registerStorage(ctx *quickjs.Context, values map[string]string, lastRefreshed *time.Time) (modified func() bool, err error) {
+	var dirty bool
+	globals := ctx.Globals()
+	globals.Set("__storageGet", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 1 {
+			return ctx.ThrowTypeError("storage.get: missing argument")
+		}
+		if value, ok := values[args[0].String()]; ok {
+			return ctx.NewString(value)
+		}
+		return ctx.NewNull()
+	}))
+	globals.Set("__storageSet", ctx.NewFunction(func(ctx *quickjs.Context, this *quickjs.Value, args []*quickjs.Value) *quickjs.Value {
+		if len(args) < 2 {
+			return ctx.ThrowTypeError("storage.set: missing arguments")
+		}
+		if args[1].IsNull() {
+			delete(values, args[0].String())
+		} else {
+			values[args[0].String()] = args[1].String()
+		}
+		dirty = true
+		return ctx.NewUndefined()
+	}))
+	if lastRefreshed != nil {
+		globals.Set("__lastRefreshed", ctx.NewInt64(lastRefreshed.UnixMilli()))
+	} else {
+		globals.Set("__lastRefreshed", ctx.NewNull())
+	}
+
+	ret := ctx.Eval(storagePrelude)
+	defer ret.Free()
+	if ret.IsException() {
+		return nil, ctx.Exception()
+	}
+	return func() bool { return dirty }, nil
+}
+
+func scriptFetch(reqCtx context.Context, url string, options *scriptRequest, client *http.Client) (*scriptResponse, error) {
+	method := cmp.Or(strings.ToUpper(options.Method), http.MethodGet)
+	var body io.Reader
+	if options.Body != nil {
+		if method == http.MethodGet || method == http.MethodHead {
+			return nil, errors.New("fetch: request with GET/HEAD method cannot have body")
+		}
+		body = strings.NewReader(*options.Body)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", utils.USER_AGENT)
+	for key, val := range options.Headers {
+		req.Header.Set(key, val)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	r, err := DecodeBody(resp, "")
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &scriptResponse{
+		URL:     resp.Request.URL.String(),
+		Status:  resp.StatusCode,
+		Headers: make(map[string]string, len(resp.Header)),
+		Body:    utils.BytesToString(b),
+	}
+	for key := range resp.Header {
+		res.Headers[strings.ToLower(key)] = resp.Header.Get(key)
+	}
+	return res, nil
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScriptFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api":
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, `{"method": %q, "token": %q, "body": %q}`, r.Method, r.Header.Get("X-Token"), body)
+		case "/page":
+			fmt.Fprint(w, `<ul><li><a href="/1">One</a></li><li><a href="/2"><b>Two</b></a></li></ul>`)
+		}
+	}))
+	defer server.Close()
+
+	rule := &JavaScriptRule{Script: fmt.Sprintf(`
+		const api = fetch('%[1]s/api', {method: 'POST', headers: {'X-Token': 'secret'}, body: 'query'}).json()
+		const doc = parseHTML(fetch('%[1]s/page').text())
+		module.exports = {
+			title: [api.method, api.token, api.body].join(' '),
+			items: doc.querySelectorAll('li a').map(a => ({
+				url: a.getAttribute('href'),
+				title: a.textContent,
+				content_html: a.innerHTML,
+			})),
+		}
+	`, server.URL)}
+	have, err := rule.Apply(server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Feed{
+		Title: "POST secret query",
+		Items: []Item{
+			{URL: "/1", Title: "One", Content: "One"},
+			{URL: "/2", Title: "Two", Content: "<b>Two</b>"},
+		},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestScriptLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		rule JavaScriptRule
+		want string
+	}{
+		{JavaScriptRule{Script: `for (;;) {}`, Timeout: 1}, "script limit exceeded: timeout of 1s"},
+		// requests are canceled too
+		{JavaScriptRule{Script: `fetch('` + server.URL + `')`, Timeout: 1}, "script limit exceeded: timeout of 1s"},
+		{JavaScriptRule{Script: `'x'.repeat(16 << 20)`, MemoryLimit: 8}, "script limit exceeded: memory limit of 8 MiB"},
+		// no memory left for the error itself
+		{JavaScriptRule{Script: `const a = []; for (;;) a.push('x'.repeat(1024))`, MemoryLimit: 8}, "script limit exceeded: memory limit of 8 MiB"},
+		{JavaScriptRule{Script: `const f = n => f(n + 1) + 1; f(0)`, MaxStackSize: 64}, "script limit exceeded: max stack size of 64 KiB"},
+	}
+	for _, testcase := range testcases {
+		_, err := testcase.rule.Apply(server.Client())
+		if !errors.Is(err, ErrScriptLimit) || err.Error() != testcase.want {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.rule.Script, testcase.want, err)
+		}
+	}
+
+	// other errors are not limits
+	for _, script := range []string{
+		`throw new Error('failed')`,
+		`throw new Error('out of memory')`,
+		`throw null`,
+	} {
+		rule := JavaScriptRule{Script: script}
+		if _, err := rule.Apply(server.Client()); err == nil || errors.Is(err, ErrScriptLimit) {
+			t.Fatalf("%s\nwant script error, have %#v", script, err)
+		}
+	}
+}
+
+type memoryScriptStorage struct {
+	values map[string]string
+	saves  int
+}
+
+func (s *memoryScriptStorage) LoadScriptValues() (map[string]string, error) {
+	return maps.Clone(s.values), nil
+}
+
+func (s *memoryScriptStorage) SaveScriptValues(values map[string]string) error {
+	s.values = maps.Clone(values)
+	s.saves++
+	return nil
+}
+
+func TestScriptStorage(t *testing.T) {
+	storage := &memoryScriptStorage{}
+	lastRefreshed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testcases := []struct {
+		script        string
+		lastRefreshed *time.Time
+		title         string
+		values        map[string]string
+		saves         int
+	}{
+		{
+			`const count = (storage.get('count') ?? 0) + 1
+			storage.set('count', count)
+			storage.set('seen', {ids: [1, 2]})
+			module.exports = {title: count + ' ' + lastRefreshed}`,
+			nil,
+			"1 null",
+			map[string]string{"count": "1", "seen": `{"ids":[1,2]}`},
+			1,
+		},
+		{
+			`storage.set('count', storage.get('count') + 1)
+			storage.set('seen', undefined)
+			module.exports = {title: storage.get('seen') + ' ' + lastRefreshed.toISOString()}`,
+			&lastRefreshed,
+			"null 2024-01-02T03:04:05.000Z",
+			map[string]string{"count": "2"},
+			2,
+		},
+		// not saved if unchanged
+		{
+			`module.exports = {title: String(storage.get('count'))}`,
+			nil,
+			"2",
+			map[string]string{"count": "2"},
+			2,
+		},
+		// nor if the script fails
+		{
+			`storage.set('count', 0); throw new Error('failed')`,
+			nil,
+			"",
+			map[string]string{"count": "2"},
+			2,
+		},
+	}
+	for _, testcase := range testcases {
+		rule := &JavaScriptRule{Script: testcase.script, Storage: storage, LastRefreshed: testcase.lastRefreshed}
+		feed, err := rule.Apply(http.DefaultClient)
+		var title string
+		if err == nil {
+			title = feed.Title
+		}
+		if title != testcase.title {
+			t.Fatalf("want: %#v\nhave: %#v (%v)", testcase.title, title, err)
+		}
+		if !reflect.DeepEqual(testcase.values, storage.values) || testcase.saves != storage.saves {
+			t.Fatalf("want: %#v saved %d times\nhave: %#v saved %d times", testcase.values, testcase.saves, storage.values, storage.saves)
+		}
+	}
+}
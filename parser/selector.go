@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// selector matches HTML nodes with a CSS selector or an XPath expression.
+type selector interface {
+	MatchFirst(n *html.Node) *html.Node
+	MatchAll(n *html.Node) []*html.Node
+}
+
+// xpathSelector evaluates an XPath 1.0 expression relative to the given node.
+// Attribute and text nodes are returned as text nodes holding their value.
+type xpathSelector struct {
+	expr *xpath.Expr
+}
+
+func (s xpathSelector) MatchFirst(n *html.Node) *html.Node {
+	iter := s.expr.Select(htmlquery.CreateXPathNavigator(n))
+	if iter.MoveNext() {
+		return xpathNode(iter.Current().(*htmlquery.NodeNavigator))
+	}
+	return nil
+}
+
+func (s xpathSelector) MatchAll(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	iter := s.expr.Select(htmlquery.CreateXPathNavigator(n))
+	for iter.MoveNext() {
+		nodes = append(nodes, xpathNode(iter.Current().(*htmlquery.NodeNavigator)))
+	}
+	return nodes
+}
+
+func xpathNode(nav *htmlquery.NodeNavigator) *html.Node {
+	if nav.NodeType() == xpath.AttributeNode {
+		return &html.Node{Type: html.TextNode, Data: nav.Value()}
+	}
+	return nav.Current()
+}
+
+func compileSelector(expr, selectorType string) (selector, error) {
+	switch selectorType {
+	case "", "css":
+		return cascadia.Compile(expr)
+	case "xpath":
+		e, err := xpath.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		// expressions like count(//a) compile but never select nodes
+		doc := &html.Node{Type: html.DocumentNode}
+		if _, ok := e.Evaluate(htmlquery.CreateXPathNavigator(doc)).(*xpath.NodeIterator); !ok {
+			return nil, fmt.Errorf("XPath expression %q does not select nodes", expr)
+		}
+		return xpathSelector{e}, nil
+	}
+	return nil, fmt.Errorf("unknown selector type %q", selectorType)
+}
+
+// nodeValue returns the value of attribute key of n. Text nodes, e.g. those
+// selected by an XPath attribute expression, yield their own content.
+func nodeValue(n *html.Node, key string) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	return attr(n, key)
+}
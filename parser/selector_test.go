@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSelector(t *testing.T) {
+	root, err := html.Parse(strings.NewReader(`
+		<ul>
+			<li><a href="/1">One</a></li>
+			<li><a href="/2" title="Second">Two</a></li>
+		</ul>
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		expr         string
+		selectorType string
+		attr         string
+		want         []string
+	}{
+		{"li a", "", "href", []string{"/1", "/2"}},
+		{"a[title]", "css", "title", []string{"Second"}},
+		{"//li/a", "xpath", "href", []string{"/1", "/2"}},
+		// attribute nodes are returned as text nodes holding their value
+		{"//a/@href", "xpath", "", []string{"/1", "/2"}},
+		{"//a[@title]/text()", "xpath", "", []string{"Two"}},
+		{"//h2", "xpath", "", nil},
+	}
+	for _, testcase := range testcases {
+		sel, err := compileSelector(testcase.expr, testcase.selectorType)
+		if err != nil {
+			t.Fatalf("%s: %s", testcase.expr, err)
+		}
+		var have []string
+		for _, n := range sel.MatchAll(root) {
+			have = append(have, nodeValue(n, testcase.attr))
+		}
+		if !reflect.DeepEqual(testcase.want, have) {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.expr, testcase.want, have)
+		}
+		first := sel.MatchFirst(root)
+		if len(testcase.want) == 0 && first != nil || len(testcase.want) > 0 && nodeValue(first, testcase.attr) != testcase.want[0] {
+			t.Fatalf("%s\nwant first: %#v\nhave: %#v", testcase.expr, testcase.want, first)
+		}
+	}
+}
+
+func TestSelectorInvalid(t *testing.T) {
+	testcases := []struct {
+		expr         string
+		selectorType string
+	}{
+		{"li[", "css"},
+		{"//li[", "xpath"},
+		{"count(//a)", "xpath"},
+		{"1+1", "xpath"},
+		{"string(//h2)", "xpath"},
+		{"li", "jquery"},
+	}
+	for _, testcase := range testcases {
+		if _, err := compileSelector(testcase.expr, testcase.selectorType); err == nil {
+			t.Fatalf("%s (%s): want error", testcase.expr, testcase.selectorType)
+		}
+	}
+}
@@ -1,228 +1,582 @@
 package parser
 
 import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net/http"
 	"rsslab/utils"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/andybalholm/cascadia"
 	"github.com/buke/quickjs-go"
 	"github.com/tidwall/gjson"
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
 )
 
 type HTMLRule struct {
-	URL          string            `json:"url"`
-	Headers      map[string]string `json:"headers"`
-	Title        string            `json:"title"`
-	Items        string            `json:"items"`
-	ItemTitle    string            `json:"item_title"`
-	ItemUrl      string            `json:"item_url"`
-	ItemUrlAttr  string            `json:"item_url_attr"`
-	ItemContent  string            `json:"item_content"`
-	ItemDate     string            `json:"item_date_published"`
-	ItemDateAttr string            `json:"item_date_published_attr"`
+	URL                string            `json:"url"`
+	SelectorType       string            `json:"selector_type"` // css or xpath
+	Headers            map[string]string `json:"headers"`
+	Title              string            `json:"title"`
+	Items              string            `json:"items"`
+	ItemTitle          string            `json:"item_title"`
+	ItemUrl            string            `json:"item_url"`
+	ItemUrlAttr        string            `json:"item_url_attr"`
+	ItemGUID           string            `json:"item_guid"`
+	ItemGUIDAttr       string            `json:"item_guid_attr"`
+	ItemGUIDHash       string            `json:"item_guid_hash"` // fields hashed without GUID and URL, or none
+	ItemContent        string            `json:"item_content"`
+	ItemContentExclude string            `json:"item_content_exclude"` // selector of elements removed from content
+	ItemContentInner   bool              `json:"item_content_inner"`   // without the content element itself
+	ItemImage          string            `json:"item_image"`
+	ItemImageAttr      string            `json:"item_image_attr"` // src, data-src, srcset, style, ...
+	ItemDate           string            `json:"item_date_published"`
+	ItemDateAttr       string            `json:"item_date_published_attr"`
+	ItemDateFormat     string            `json:"item_date_format"`   // Go layout or strftime-like
+	ItemDateTimezone   string            `json:"item_date_timezone"` // for dates without time zone
+	ItemAuthor         string            `json:"item_author"`
+	Charset            string            `json:"charset"`
+	NextPage           string            `json:"next_page"`
+	MaxPages           int               `json:"max_pages"`
+
+	RequestOptions
+	DetailRule
+	Diagnostics *Diagnostics `json:"-"` // optional
 }
 
 type JSONRule struct {
-	URL           string            `json:"url"`
-	HomePageURL   string            `json:"home_page_url"`
-	Headers       map[string]string `json:"headers"`
-	Title         string            `json:"title"`
-	Items         string            `json:"items"`
-	ItemTitle     string            `json:"item_title"`
-	ItemUrl       string            `json:"item_url"`
-	ItemUrlPrefix string            `json:"item_url_prefix"`
-	ItemContent   string            `json:"item_content"`
-	ItemDate      string            `json:"item_date_published"`
+	URL                string            `json:"url"`
+	EmbeddedSelector   string            `json:"embedded_selector"` // CSS selector of element holding JSON in HTML page
+	EmbeddedRegex      string            `json:"embedded_regex"`    // over HTML page, first group or match is JSON
+	HomePageURL        string            `json:"home_page_url"`
+	Headers            map[string]string `json:"headers"`
+	Title              string            `json:"title"`
+	Items              string            `json:"items"`
+	ItemTitle          string            `json:"item_title"`
+	ItemUrl            string            `json:"item_url"`
+	ItemUrlPrefix      string            `json:"item_url_prefix"`
+	ItemGUID           string            `json:"item_guid"`
+	ItemGUIDHash       string            `json:"item_guid_hash"` // fields hashed without GUID and URL, or none
+	ItemContent        string            `json:"item_content"`
+	ItemContentExclude string            `json:"item_content_exclude"` // CSS selector of elements removed from HTML content
+	ItemImage          string            `json:"item_image"`
+	ItemDate           string            `json:"item_date_published"`
+	ItemDateFormat     string            `json:"item_date_format"`   // Go layout or strftime-like
+	ItemDateTimezone   string            `json:"item_date_timezone"` // for dates without time zone
+	ItemAuthor         string            `json:"item_author"`
+	Charset            string            `json:"charset"`
+	NextPage           string            `json:"next_page"`
+	PageURL            string            `json:"page_url"` // {page} is replaced with the page number
+	MaxPages           int               `json:"max_pages"`
+
+	RequestOptions
+	DetailRule
+	Diagnostics *Diagnostics `json:"-"` // optional
 }
 
 type JavaScriptRule struct {
-	Script string `json:"script"`
+	Script       string `json:"script"`
+	Timeout      int    `json:"timeout"`        // in seconds
+	MemoryLimit  int    `json:"memory_limit"`   // in MiB
+	MaxStackSize int    `json:"max_stack_size"` // in KiB
+
+	// Storage keeps values of the storage API between runs. Values are
+	// saved only when the script succeeds. Optional.
+	Storage ScriptStorage `json:"-"`
+	// LastRefreshed is the time of the previous run, if any.
+	LastRefreshed *time.Time `json:"-"`
+	// Diagnostics receives output of console methods. Optional.
+	Diagnostics *Diagnostics `json:"-"`
 }
 
-func (rule *HTMLRule) Apply(client *http.Client) (*Feed, error) {
-	resp, err := tryGet(rule.URL, rule.Headers, client)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	root, err := html.Parse(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+const (
+	defaultScriptTimeout      = 30
+	defaultScriptMemoryLimit  = 64
+	defaultScriptMaxStackSize = 1024
+
+	// allocated bytes short of the memory limit at which a script is
+	// considered out of memory
+	outOfMemoryMargin = 4 << 10
+)
+
+// ErrScriptLimit is returned when a script exceeds the time, memory or stack
+// limit of its rule.
+var ErrScriptLimit = errors.New("script limit exceeded")
 
+func (rule *HTMLRule) Apply(client *http.Client) (*Feed, error) {
+	d := rule.Diagnostics
+	client = d.client(client)
 	var feed Feed
 	feed.SiteURL = rule.URL
 	if rule.Title == "" {
 		rule.Title = "title"
+		if rule.SelectorType == "xpath" {
+			rule.Title = "//title"
+		}
+	}
+	titleSel, err := compileSelector(rule.Title, rule.SelectorType)
+	if err != nil {
+		return nil, err
 	}
-	s, err := cascadia.Compile(rule.Title)
+	itemsSel, err := compileSelector(rule.Items, rule.SelectorType)
 	if err != nil {
 		return nil, err
 	}
-	feed.Title = utils.CollapseWhitespace(extractText(s.MatchFirst(root)))
 
-	var titleSel, urlSel, contentSel, dateSel cascadia.Selector
+	var itemTitleSel, urlSel, guidSel, contentSel, excludeSel, imageSel, dateSel, authorSel, nextSel selector
 	if rule.ItemTitle != "" {
-		if titleSel, err = cascadia.Compile(rule.ItemTitle); err != nil {
+		if itemTitleSel, err = compileSelector(rule.ItemTitle, rule.SelectorType); err != nil {
 			return nil, err
 		}
 	}
 	if rule.ItemUrl != "" {
-		if urlSel, err = cascadia.Compile(rule.ItemUrl); err != nil {
+		if urlSel, err = compileSelector(rule.ItemUrl, rule.SelectorType); err != nil {
+			return nil, err
+		}
+	}
+	if rule.ItemGUID != "" {
+		if guidSel, err = compileSelector(rule.ItemGUID, rule.SelectorType); err != nil {
 			return nil, err
 		}
 	}
 	if rule.ItemContent != "" {
-		if contentSel, err = cascadia.Compile(rule.ItemContent); err != nil {
+		if contentSel, err = compileSelector(rule.ItemContent, rule.SelectorType); err != nil {
+			return nil, err
+		}
+	}
+	if rule.ItemContentExclude != "" {
+		if excludeSel, err = compileSelector(rule.ItemContentExclude, rule.SelectorType); err != nil {
+			return nil, err
+		}
+	}
+	if rule.ItemImage != "" {
+		if imageSel, err = compileSelector(rule.ItemImage, rule.SelectorType); err != nil {
 			return nil, err
 		}
 	}
 	if rule.ItemDate != "" {
-		if dateSel, err = cascadia.Compile(rule.ItemDate); err != nil {
+		if dateSel, err = compileSelector(rule.ItemDate, rule.SelectorType); err != nil {
 			return nil, err
 		}
 	}
-
-	s, err = cascadia.Compile(rule.Items)
+	if rule.ItemAuthor != "" {
+		if authorSel, err = compileSelector(rule.ItemAuthor, rule.SelectorType); err != nil {
+			return nil, err
+		}
+	}
+	if rule.NextPage != "" {
+		if nextSel, err = compileSelector(rule.NextPage, rule.SelectorType); err != nil {
+			return nil, err
+		}
+	}
+	if rule.ItemUrlAttr == "" {
+		rule.ItemUrlAttr = "href"
+	}
+	if rule.ItemImageAttr == "" {
+		rule.ItemImageAttr = "src"
+	}
+	dates, err := newDateParser(rule.ItemDateFormat, rule.ItemDateTimezone)
+	if err != nil {
+		return nil, err
+	}
+	hashFields, err := guidFields(rule.ItemGUIDHash)
 	if err != nil {
 		return nil, err
 	}
-	items := s.MatchAll(root)
-	feed.Items = make([]Item, 0, len(items))
-	for _, item := range items {
-		var i Item
 
-		title := item
-		if titleSel != nil {
-			title = titleSel.MatchFirst(item)
+	visited := make(map[string]struct{})
+	pageUrl := rule.URL
+	for page := 1; page <= maxPages(rule.MaxPages) && pageUrl != ""; page++ {
+		visited[pageUrl] = struct{}{}
+		root, err := rule.fetch(pageUrl, client)
+		if err != nil {
+			return nil, err
 		}
-		i.Title = utils.CollapseWhitespace(extractText(title))
-
-		url := item
-		if urlSel != nil {
-			url = urlSel.MatchFirst(item)
+		if page == 1 {
+			title := titleSel.MatchFirst(root)
+			d.matchNode(page, "title", rule.Title, title)
+			feed.Title = utils.CollapseWhitespace(extractText(title))
 		}
-		if rule.ItemUrlAttr == "" {
-			rule.ItemUrlAttr = "href"
+
+		items := itemsSel.MatchAll(root)
+		d.match(page, "items", rule.Items, len(items), "")
+		if len(items) == 0 {
+			break
 		}
-		if url != nil {
-			for _, attr := range url.Attr {
-				if attr.Key == rule.ItemUrlAttr {
-					i.URL = utils.AbsoluteUrl(attr.Val, rule.URL)
-					i.GUID = i.URL
-					break
-				}
+		for _, item := range items {
+			var i Item
+
+			title := item
+			if itemTitleSel != nil {
+				title = itemTitleSel.MatchFirst(item)
+				d.matchNode(page, "item_title", rule.ItemTitle, title)
 			}
-		}
+			i.Title = utils.CollapseWhitespace(extractText(title))
 
-		content := item
-		if contentSel != nil {
-			content = contentSel.MatchFirst(item)
-		}
-		if content != nil {
-			var b strings.Builder
-			if err := html.Render(&b, content); err != nil {
-				return nil, err
+			url := item
+			if urlSel != nil {
+				url = urlSel.MatchFirst(item)
+				d.matchNode(page, "item_url", rule.ItemUrl, url)
+			}
+			if url != nil {
+				if href := nodeValue(url, rule.ItemUrlAttr); href != "" {
+					i.URL = utils.AbsoluteUrl(strings.TrimSpace(href), pageUrl)
+				}
 			}
-			i.Content = b.String()
-		}
 
-		date := item
-		if dateSel != nil {
-			date = dateSel.MatchFirst(item)
-		}
-		if rule.ItemDateAttr != "" {
-			if date != nil {
-				for _, attr := range date.Attr {
-					if attr.Key == rule.ItemDateAttr {
-						i.Date = parseDate(attr.Val)
-						break
+			if guidSel != nil {
+				guid := guidSel.MatchFirst(item)
+				d.matchNode(page, "item_guid", rule.ItemGUID, guid)
+				if guid != nil {
+					if rule.ItemGUIDAttr != "" {
+						i.GUID = strings.TrimSpace(nodeValue(guid, rule.ItemGUIDAttr))
+					} else {
+						i.GUID = utils.CollapseWhitespace(extractText(guid))
 					}
 				}
 			}
-		} else {
-			i.Date = parseDate(extractText(date))
+
+			content := item
+			if contentSel != nil {
+				content = contentSel.MatchFirst(item)
+				d.matchNode(page, "item_content", rule.ItemContent, content)
+			}
+			if content != nil {
+				c, err := renderContent(content, excludeSel, rule.ItemContentInner)
+				if err != nil {
+					return nil, err
+				}
+				i.Content = c
+			}
+
+			if imageSel != nil {
+				image := imageSel.MatchFirst(item)
+				d.matchNode(page, "item_image", rule.ItemImage, image)
+				if src := imageSource(image, rule.ItemImageAttr); src != "" {
+					i.ImageURL = utils.AbsoluteUrl(src, pageUrl)
+				}
+			}
+
+			date := item
+			if dateSel != nil {
+				date = dateSel.MatchFirst(item)
+				d.matchNode(page, "item_date_published", rule.ItemDate, date)
+			}
+			if rule.ItemDateAttr != "" {
+				if date != nil {
+					i.Date = dates.parse(nodeValue(date, rule.ItemDateAttr))
+				}
+			} else {
+				i.Date = dates.parse(extractText(date))
+			}
+
+			if authorSel != nil {
+				author := authorSel.MatchFirst(item)
+				d.matchNode(page, "item_author", rule.ItemAuthor, author)
+				if name := utils.CollapseWhitespace(extractText(author)); name != "" {
+					i.Authors = []Author{{Name: name}}
+				}
+			}
+
+			// hash content as found, resolved URLs differ between pages
+			i.setGUID(hashFields)
+			i.Content = resolveContentURLs(i.Content, pageUrl)
+			feed.Items = append(feed.Items, i)
+		}
+
+		var nextUrl string
+		if nextSel != nil {
+			next := nextSel.MatchFirst(root)
+			d.matchNode(page, "next_page", rule.NextPage, next)
+			if next != nil {
+				nextUrl = nextPage(nodeValue(next, "href"), pageUrl, visited)
+			}
 		}
+		pageUrl = nextUrl
+	}
 
-		feed.Items = append(feed.Items, i)
+	if rule.enabled() {
+		rule.DetailSelectorType = cmp.Or(rule.DetailSelectorType, rule.SelectorType)
+		if err := rule.applyDetails(feed.Items, rule.Headers, rule.Charset, client, dates, d); err != nil {
+			return nil, err
+		}
 	}
 
 	slices.SortStableFunc(feed.Items, cmpItem)
 	return &feed, nil
 }
 
-func (rule *JSONRule) Apply(client *http.Client) (*Feed, error) {
-	resp, err := tryGet(rule.URL, rule.Headers, client)
+func (rule *HTMLRule) fetch(url string, client *http.Client) (*html.Node, error) {
+	resp, err := tryRequest(url, rule.Headers, &rule.RequestOptions, client)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
+	body, err := DecodeBody(resp, rule.Charset)
 	if err != nil {
 		return nil, err
 	}
-	j := gjson.ParseBytes(b)
+	return html.Parse(body)
+}
 
+func (rule *JSONRule) Apply(client *http.Client) (*Feed, error) {
+	d := rule.Diagnostics
+	client = d.client(client)
 	feed := Feed{SiteURL: rule.HomePageURL}
-	if rule.Title != "" {
-		feed.Title = j.Get(rule.Title).String()
+	dates, err := newDateParser(rule.ItemDateFormat, rule.ItemDateTimezone)
+	if err != nil {
+		return nil, err
 	}
-
-	var items []gjson.Result
-	if rule.Items == "" {
-		items = j.Array()
-	} else {
-		items = j.Get(rule.Items).Array()
+	hashFields, err := guidFields(rule.ItemGUIDHash)
+	if err != nil {
+		return nil, err
 	}
-	feed.Items = make([]Item, 0, len(items))
-	for _, item := range items {
-		var i Item
-
-		if rule.ItemTitle != "" {
-			i.Title = item.Get(rule.ItemTitle).String()
+	var excludeSel selector
+	if rule.ItemContentExclude != "" {
+		if excludeSel, err = compileSelector(rule.ItemContentExclude, "css"); err != nil {
+			return nil, err
 		}
+	}
+	embedded, err := newEmbeddedJSON(rule.EmbeddedSelector, rule.EmbeddedRegex)
+	if err != nil {
+		return nil, err
+	}
 
-		if rule.ItemUrl != "" {
-			i.URL = item.Get(rule.ItemUrl).String()
-			if rule.ItemUrlPrefix != "" {
-				i.URL = rule.ItemUrlPrefix + i.URL
+	visited := make(map[string]struct{})
+	pageUrl := rule.URL
+	for page := 1; page <= maxPages(rule.MaxPages) && pageUrl != ""; page++ {
+		visited[pageUrl] = struct{}{}
+		b, err := rule.fetch(pageUrl, client)
+		if err != nil {
+			return nil, err
+		}
+		if embedded != nil {
+			if b, err = embedded.extract(b, page, d); err != nil {
+				return nil, fmt.Errorf("%s: %w", pageUrl, err)
 			}
-			i.GUID = i.URL
+		}
+		j := gjson.ParseBytes(b)
+		if page == 1 && rule.Title != "" {
+			title := j.Get(rule.Title)
+			d.matchJSON(page, "title", rule.Title, title)
+			feed.Title = title.String()
 		}
 
-		if rule.ItemContent != "" {
-			i.Content = item.Get(rule.ItemContent).String()
+		var items []gjson.Result
+		if rule.Items == "" {
+			items = j.Array()
+		} else {
+			items = j.Get(rule.Items).Array()
+		}
+		d.match(page, "items", rule.Items, len(items), "")
+		if len(items) == 0 {
+			break
 		}
+		for _, item := range items {
+			var i Item
 
-		if rule.ItemDate != "" {
-			i.Date = parseDate(item.Get(rule.ItemDate).String())
+			if rule.ItemTitle != "" {
+				title := item.Get(rule.ItemTitle)
+				d.matchJSON(page, "item_title", rule.ItemTitle, title)
+				i.Title = title.String()
+			}
+
+			if rule.ItemUrl != "" {
+				url := item.Get(rule.ItemUrl)
+				d.matchJSON(page, "item_url", rule.ItemUrl, url)
+				i.URL = url.String()
+				if rule.ItemUrlPrefix != "" {
+					i.URL = rule.ItemUrlPrefix + i.URL
+				}
+			}
+			// base of relative URLs in the item, which may be relative itself
+			itemUrl := utils.AbsoluteUrl(i.URL, cmp.Or(rule.HomePageURL, pageUrl))
+
+			if rule.ItemGUID != "" {
+				guid := item.Get(rule.ItemGUID)
+				d.matchJSON(page, "item_guid", rule.ItemGUID, guid)
+				i.GUID = strings.TrimSpace(guid.String())
+			}
+
+			if rule.ItemContent != "" {
+				content := item.Get(rule.ItemContent)
+				d.matchJSON(page, "item_content", rule.ItemContent, content)
+				i.Content = content.String()
+				if excludeSel != nil {
+					if i.Content, err = excludeContent(i.Content, excludeSel); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if rule.ItemImage != "" {
+				image := item.Get(rule.ItemImage)
+				d.matchJSON(page, "item_image", rule.ItemImage, image)
+				if src := strings.TrimSpace(image.String()); src != "" {
+					i.ImageURL = utils.AbsoluteUrl(src, itemUrl)
+				}
+			}
+
+			if rule.ItemDate != "" {
+				date := item.Get(rule.ItemDate)
+				d.matchJSON(page, "item_date_published", rule.ItemDate, date)
+				i.Date = dates.parse(date.String())
+			}
+
+			if rule.ItemAuthor != "" {
+				author := item.Get(rule.ItemAuthor)
+				d.matchJSON(page, "item_author", rule.ItemAuthor, author)
+				for _, a := range author.Array() {
+					if a.IsObject() {
+						a = a.Get("name")
+					}
+					if name := strings.TrimSpace(a.String()); name != "" {
+						i.Authors = append(i.Authors, Author{Name: name})
+					}
+				}
+			}
+
+			// hash content as found, resolved URLs differ between pages
+			i.setGUID(hashFields)
+			i.Content = resolveContentURLs(i.Content, itemUrl)
+			feed.Items = append(feed.Items, i)
+		}
+
+		var nextUrl string
+		if rule.NextPage != "" {
+			next := j.Get(rule.NextPage)
+			d.matchJSON(page, "next_page", rule.NextPage, next)
+			nextUrl = nextPage(next.String(), pageUrl, visited)
+		} else if rule.PageURL != "" {
+			nextUrl = nextPage(strings.ReplaceAll(rule.PageURL, "{page}", strconv.Itoa(page+1)), rule.URL, visited)
 		}
+		pageUrl = nextUrl
+	}
 
-		feed.Items = append(feed.Items, i)
+	if rule.enabled() {
+		if err := rule.applyDetails(feed.Items, rule.Headers, "", client, dates, d); err != nil {
+			return nil, err
+		}
 	}
 
 	slices.SortStableFunc(feed.Items, cmpItem)
 	return &feed, nil
 }
 
+func (rule *JSONRule) fetch(url string, client *http.Client) ([]byte, error) {
+	resp, err := tryRequest(url, rule.Headers, &rule.RequestOptions, client)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := DecodeBody(resp, rule.Charset)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(body)
+}
+
+const defaultMaxPages = 10
+
+// maxPages returns the number of pages a rule fetches at most.
+func maxPages(n int) int {
+	return positiveOr(n, defaultMaxPages)
+}
+
+// positiveOr returns n if it is positive, or def otherwise.
+func positiveOr(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+// nextPage resolves href against base, returning an empty string when
+// there is no next page or it has been fetched already.
+func nextPage(href, base string, visited map[string]struct{}) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	url := utils.AbsoluteUrl(href, base)
+	if _, ok := visited[url]; ok {
+		return ""
+	}
+	return url
+}
+
 func (rule *JavaScriptRule) Apply(client *http.Client) (*Feed, error) {
-	rt := quickjs.NewRuntime()
+	timeout := time.Duration(positiveOr(rule.Timeout, defaultScriptTimeout)) * time.Second
+	memoryLimit := positiveOr(rule.MemoryLimit, defaultScriptMemoryLimit)
+	maxStackSize := positiveOr(rule.MaxStackSize, defaultScriptMaxStackSize)
+
+	rt := quickjs.NewRuntime(
+		quickjs.WithMemoryLimit(uint64(memoryLimit)<<20),
+		quickjs.WithMaxStackSize(uint64(maxStackSize)<<10),
+	)
 	defer rt.Close()
+	deadline := time.Now().Add(timeout)
+	rt.SetInterruptHandler(func() int {
+		if time.Now().After(deadline) {
+			return 1
+		}
+		return 0
+	})
+	// bound requests made by fetch() with the same deadline
+	reqCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
 	ctx := rt.NewContext()
 	defer ctx.Close()
+	if err := registerHostFunctions(ctx, reqCtx, rule.Diagnostics.client(client), rule.Diagnostics); err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if rule.Storage != nil {
+		var err error
+		if values, err = rule.Storage.LoadScriptValues(); err != nil {
+			return nil, err
+		}
+	}
+	if values == nil {
+		values = make(map[string]string)
+	}
+	modified, err := registerStorage(ctx, values, rule.LastRefreshed)
+	if err != nil {
+		return nil, err
+	}
 
 	module := ctx.NewObject()
 	ctx.Globals().Set("module", module)
 	ret := ctx.Eval(rule.Script)
 	defer ret.Free()
 	if ret.IsException() {
-		return nil, ctx.Exception()
+		err := ctx.Exception()
+		var jsErr *quickjs.Error
+		errors.As(err, &jsErr)
+		switch {
+		case time.Now().After(deadline):
+			return nil, fmt.Errorf("%w: timeout of %s", ErrScriptLimit, timeout)
+		case jsErr != nil && jsErr.Name == "InternalError" && jsErr.Message == "out of memory",
+			// QuickJS throws null instead when there is no memory left
+			// for the error itself
+			jsErr == nil && rt.MemoryUsage().MallocSize > int64(memoryLimit)<<20-outOfMemoryMargin:
+			return nil, fmt.Errorf("%w: memory limit of %d MiB", ErrScriptLimit, memoryLimit)
+		case jsErr != nil && jsErr.Name == "RangeError" && jsErr.Message == "Maximum call stack size exceeded":
+			return nil, fmt.Errorf("%w: max stack size of %d KiB", ErrScriptLimit, maxStackSize)
+		}
+		return nil, err
 	}
 
 	var feed Feed
@@ -234,6 +588,11 @@ func (rule *JavaScriptRule) Apply(client *http.Client) (*Feed, error) {
 			return nil, err
 		}
 	}
+	if rule.Storage != nil && modified() {
+		if err := rule.Storage.SaveScriptValues(values); err != nil {
+			return nil, err
+		}
+	}
 	return &feed, nil
 }
 
@@ -248,17 +607,70 @@ var retryStatusCodes = map[int]struct{}{
 	http.StatusGatewayTimeout:      {},
 }
 
-func tryGet(url string, headers map[string]string, client *http.Client) (resp *http.Response, err error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// RequestOptions customizes the requests of listing pages.
+type RequestOptions struct {
+	Method      string `json:"method"` // GET, or POST if there is a body
+	Body        string `json:"body"`
+	ContentType string `json:"content_type"` // of the body
+}
+
+// NewRequest returns a request for url with the options and headers, which
+// take precedence.
+func (o *RequestOptions) NewRequest(url string, headers map[string]string) (*http.Request, error) {
+	method := strings.ToUpper(o.Method)
+	if method == "" {
+		method = http.MethodGet
+		if o.Body != "" {
+			method = http.MethodPost
+		}
+	}
+	var body io.Reader
+	if o.Body != "" {
+		body = strings.NewReader(o.Body)
+	}
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("User-Agent", utils.USER_AGENT)
+	if o.ContentType != "" {
+		req.Header.Set("Content-Type", o.ContentType)
+	}
 	for key, val := range headers {
 		req.Header.Set(key, val)
 	}
-	const maxTry = 3
+	return req, nil
+}
+
+var idempotentMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+	http.MethodPut:     {},
+	http.MethodDelete:  {},
+}
+
+// tryRequest sends a request with opts, which may be nil for GET requests.
+// Requests with idempotent methods are retried on temporary failures.
+func tryRequest(url string, headers map[string]string, opts *RequestOptions, client *http.Client) (resp *http.Response, err error) {
+	if opts == nil {
+		opts = &RequestOptions{}
+	}
+	req, err := opts.NewRequest(url, headers)
+	if err != nil {
+		return nil, err
+	}
+	maxTry := 3
+	if _, ok := idempotentMethods[req.Method]; !ok {
+		maxTry = 1
+	}
 	for attempt := 1; attempt <= maxTry; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
 		resp, err = client.Do(req)
 		if err == nil {
 			if !utils.IsErrorResponse(resp.StatusCode) {
@@ -277,9 +689,48 @@ func tryGet(url string, headers map[string]string, client *http.Client) (resp *h
 	return
 }
 
+// DecodeBody returns the response body converted to UTF-8. The encoding is
+// taken from override if set, otherwise it is determined by the BOM or the
+// Content-Type header. Only HTML documents are sniffed for a <meta>
+// declaration, other text defaults to UTF-8.
+func DecodeBody(resp *http.Response, override string) (io.Reader, error) {
+	if override != "" {
+		e, _ := charset.Lookup(override)
+		if e == nil {
+			return nil, fmt.Errorf("unknown charset %q", override)
+		}
+		return e.NewDecoder().Reader(resp.Body), nil
+	}
+
+	body := bufio.NewReader(resp.Body)
+	head, _ := body.Peek(512)
+	if bytes.HasPrefix(head, []byte("\xef\xbb\xbf")) {
+		body.Discard(3)
+		return body, nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	}
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" && params["charset"] == "" &&
+		!bytes.HasPrefix(head, []byte("\xfe\xff")) && !bytes.HasPrefix(head, []byte("\xff\xfe")) {
+		// charset.NewReader would fall back to windows-1252
+		return body, nil
+	}
+	r, err := charset.NewReader(body, contentType)
+	if err == io.EOF {
+		// empty body
+		return body, nil
+	}
+	return r, err
+}
+
 func extractText(node *html.Node) string {
 	if node == nil {
 		return ""
+	} else if node.Type == html.TextNode {
+		return node.Data
 	}
 	var b strings.Builder
 	for n := range node.Descendants() {
@@ -290,6 +741,22 @@ func extractText(node *html.Node) string {
 	return b.String()
 }
 
+// imageSource returns the image URL in attribute key of n, which may be a
+// srcset or a style with a background image.
+func imageSource(n *html.Node, key string) string {
+	if n == nil {
+		return ""
+	}
+	src := strings.TrimSpace(nodeValue(n, key))
+	switch key {
+	case "srcset", "data-srcset":
+		return largestSrc(src)
+	case "style":
+		return styleImage(src)
+	}
+	return src
+}
+
 func cmpItem(a, b Item) int {
 	if b.Date == nil {
 		if a.Date == nil {
@@ -0,0 +1,300 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPagination(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/html/1":
+			fmt.Fprint(w, `<li>One</li><a class="next" href="2">Next</a>`)
+		case "/html/2":
+			fmt.Fprint(w, `<li>Two</li><a class="next" href="3">Next</a>`)
+		case "/html/3":
+			// links back to a fetched page
+			fmt.Fprint(w, `<li>Three</li><a class="next" href="1">Next</a>`)
+		case "/json":
+			page := r.URL.Query().Get("page")
+			if page == "3" {
+				fmt.Fprint(w, `{"items": []}`)
+			} else {
+				fmt.Fprintf(w, `{"items": [{"title": "%s"}]}`, cmp.Or(page, "1"))
+			}
+		case "/json/next":
+			fmt.Fprintf(w, `{"items": [{"title": "%s"}], "next": "?cursor=%d"}`, r.URL.Query().Get("cursor"), len(requests))
+		}
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		rule interface {
+			Apply(*http.Client) (*Feed, error)
+		}
+		titles   []string
+		requests []string
+	}{
+		{
+			&HTMLRule{URL: server.URL + "/html/1", Items: "li", ItemTitle: "li", NextPage: "a.next"},
+			[]string{"One", "Two", "Three"},
+			[]string{"/html/1", "/html/2", "/html/3"},
+		},
+		{
+			&HTMLRule{URL: server.URL + "/html/1", Items: "li", ItemTitle: "li", NextPage: "a.next", MaxPages: 2},
+			[]string{"One", "Two"},
+			[]string{"/html/1", "/html/2"},
+		},
+		{
+			// stops at the first page without items
+			&JSONRule{URL: server.URL + "/json", Items: "items", ItemTitle: "title", PageURL: "/json?page={page}"},
+			[]string{"1", "2"},
+			[]string{"/json", "/json?page=2", "/json?page=3"},
+		},
+		{
+			&JSONRule{URL: server.URL + "/json/next", Items: "items", ItemTitle: "title", NextPage: "next", MaxPages: 3},
+			[]string{"", "1", "2"},
+			[]string{"/json/next", "/json/next?cursor=1", "/json/next?cursor=2"},
+		},
+	}
+	for _, testcase := range testcases {
+		requests = nil
+		feed, err := testcase.rule.Apply(server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, item := range feed.Items {
+			titles = append(titles, item.Title)
+		}
+		if !reflect.DeepEqual(testcase.titles, titles) {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.titles, titles)
+		}
+		if !reflect.DeepEqual(testcase.requests, requests) {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.requests, requests)
+		}
+	}
+}
+
+func TestItemImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json" {
+			fmt.Fprint(w, `{"items": [{"url": "/posts/1", "image": "1.png"}, {"image": "https://cdn.example.com/2.png"}]}`)
+			return
+		}
+		fmt.Fprint(w, `
+			<li><img src="placeholder.gif" data-src="lazy.png" srcset="small.png 1x, large.png 2x"></li>
+			<li><div style="background-image: url(/background.png)"></div></li>
+			<li>No image</li>
+		`)
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		rule interface {
+			Apply(*http.Client) (*Feed, error)
+		}
+		want []string
+	}{
+		{
+			&HTMLRule{URL: server.URL + "/list/", Items: "li", ItemImage: "img"},
+			[]string{server.URL + "/list/placeholder.gif", "", ""},
+		},
+		{
+			&HTMLRule{URL: server.URL + "/list/", Items: "li", ItemImage: "img", ItemImageAttr: "data-src"},
+			[]string{server.URL + "/list/lazy.png", "", ""},
+		},
+		{
+			&HTMLRule{URL: server.URL + "/list/", Items: "li", ItemImage: "img", ItemImageAttr: "srcset"},
+			[]string{server.URL + "/list/large.png", "", ""},
+		},
+		{
+			&HTMLRule{URL: server.URL + "/list/", Items: "li", ItemImage: "div", ItemImageAttr: "style"},
+			[]string{"", server.URL + "/background.png", ""},
+		},
+		{
+			&HTMLRule{URL: server.URL + "/list/", SelectorType: "xpath", Items: "//li", ItemImage: ".//img/@data-src"},
+			[]string{server.URL + "/list/lazy.png", "", ""},
+		},
+		{
+			// relative to the item URL, which is relative to the page
+			&JSONRule{URL: server.URL + "/json", Items: "items", ItemUrl: "url", ItemImage: "image"},
+			[]string{server.URL + "/posts/1.png", "https://cdn.example.com/2.png"},
+		},
+		{
+			&JSONRule{URL: server.URL + "/json", HomePageURL: "https://example.com/blog/", Items: "items", ItemUrl: "url", ItemImage: "image"},
+			[]string{"https://example.com/posts/1.png", "https://cdn.example.com/2.png"},
+		},
+	}
+	for _, testcase := range testcases {
+		feed, err := testcase.rule.Apply(server.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have []string
+		for _, item := range feed.Items {
+			have = append(have, item.ImageURL)
+		}
+		if !reflect.DeepEqual(testcase.want, have) {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.want, have)
+		}
+	}
+}
+
+func TestItemContentExclude(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json" {
+			fmt.Fprint(w, `{"items": [{"title": "One", "content": "<p>Text</p><div class=\"share\">Share</div>"}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<article><h2>One</h2><p>Text</p><time class="date">2024-01-02</time><div class="share">Share</div></article>`)
+	}))
+	defer server.Close()
+
+	htmlRule := &HTMLRule{
+		URL:                server.URL,
+		Items:              "article",
+		ItemTitle:          "h2",
+		ItemContent:        "article",
+		ItemContentExclude: "h2, .date, .share",
+		ItemContentInner:   true,
+		ItemDate:           ".date",
+	}
+	feed, err := htmlRule.Apply(server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// excluded elements are still available to other fields
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	want := []Item{{Title: "One", Content: "<p>Text</p>", Date: &date}}
+	var have []Item
+	for _, item := range feed.Items {
+		have = append(have, Item{Title: item.Title, Content: item.Content, Date: item.Date})
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+
+	jsonRule := &JSONRule{
+		URL:                server.URL + "/json",
+		Items:              "items",
+		ItemTitle:          "title",
+		ItemContent:        "content",
+		ItemContentExclude: ".share",
+	}
+	if feed, err = jsonRule.Apply(server.Client()); err != nil {
+		t.Fatal(err)
+	}
+	if len(feed.Items) != 1 || feed.Items[0].Content != "<p>Text</p>" {
+		t.Fatalf("want: %#v\nhave: %#v", "<p>Text</p>", feed.Items)
+	}
+}
+
+func TestRequestOptions(t *testing.T) {
+	testcases := []struct {
+		options RequestOptions
+		headers map[string]string
+		method  string
+		body    string
+		ctype   string
+	}{
+		{RequestOptions{}, nil, "GET", "", ""},
+		{RequestOptions{Body: "{}"}, nil, "POST", "{}", ""},
+		{RequestOptions{Method: "put", Body: "x", ContentType: "text/plain"}, nil, "PUT", "x", "text/plain"},
+		// headers take precedence
+		{RequestOptions{Body: "{}", ContentType: "text/plain"}, map[string]string{"Content-Type": "application/json"}, "POST", "{}", "application/json"},
+	}
+	for _, testcase := range testcases {
+		req, err := testcase.options.NewRequest("https://example.com", testcase.headers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body string
+		if req.Body != nil {
+			b, _ := io.ReadAll(req.Body)
+			body = string(b)
+		}
+		if req.Method != testcase.method || body != testcase.body || req.Header.Get("Content-Type") != testcase.ctype {
+			t.Fatalf("%#v\nwant: %s %#v %#v\nhave: %s %#v %#v", testcase.options, testcase.method, testcase.body, testcase.ctype,
+				req.Method, body, req.Header.Get("Content-Type"))
+		}
+	}
+	if _, err := (&RequestOptions{Method: "GET POST"}).NewRequest("https://example.com", nil); err == nil {
+		t.Fatal("want error for invalid method")
+	}
+}
+
+func TestTryRequestRetry(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+string(b))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testcases := []struct {
+		options *RequestOptions
+		want    []string
+	}{
+		{nil, []string{"GET ", "GET ", "GET "}},
+		// the body is sent again
+		{&RequestOptions{Method: "PUT", Body: "x"}, []string{"PUT x", "PUT x", "PUT x"}},
+		// not idempotent
+		{&RequestOptions{Body: "x"}, []string{"POST x"}},
+	}
+	for _, testcase := range testcases {
+		bodies = nil
+		if _, err := tryRequest(server.URL, nil, testcase.options, server.Client()); err == nil {
+			t.Fatal("want error")
+		}
+		if !reflect.DeepEqual(testcase.want, bodies) {
+			t.Fatalf("want: %#v\nhave: %#v", testcase.want, bodies)
+		}
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	padding := strings.Repeat(" ", 1100)
+	testcases := []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		// UTF-8 is not detected from the first 1 KB
+		{"application/json", padding + `{"title": "中文标题 café"}`, padding + `{"title": "中文标题 café"}`},
+		{"", padding + `{"title": "café"}`, padding + `{"title": "café"}`},
+		{"application/json", "\xef\xbb\xbf{}", "{}"},
+		{"application/json; charset=iso-8859-1", "{\"title\": \"caf\xe9\"}", `{"title": "café"}`},
+		{"text/html", `<meta charset="iso-8859-1"><title>caf` + "\xe9</title>", `<meta charset="iso-8859-1"><title>café</title>`},
+		{"", `<!DOCTYPE html><meta charset="iso-8859-1"><title>caf` + "\xe9</title>", `<!DOCTYPE html><meta charset="iso-8859-1"><title>café</title>`},
+		{"text/html", "", ""},
+	}
+	for _, testcase := range testcases {
+		resp := &http.Response{
+			Header: http.Header{"Content-Type": {testcase.contentType}},
+			Body:   io.NopCloser(strings.NewReader(testcase.body)),
+		}
+		r, err := DecodeBody(resp, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := string(b); have != testcase.want {
+			t.Fatalf("content type: %s\nwant: %#v\nhave: %#v", testcase.contentType, testcase.want, have)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"rsslab/parser"
+	"rsslab/utils"
+	"sync"
+)
+
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss",
+	"/feed.xml",
+	"/rss.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed.json",
+}
+
+// discover returns candidate feeds for rawUrl. If rawUrl is a feed itself,
+// it is the only candidate. Otherwise feeds advertised by the web page are
+// returned, falling back to probing common feed locations of the site.
+func (s *Server) discover(rawUrl string) ([]parser.Link, error) {
+	link, b, finalUrl, err := s.probe(rawUrl)
+	if err != nil {
+		return nil, err
+	} else if link != nil {
+		return []parser.Link{*link}, nil
+	}
+	return s.discoverPage(b, finalUrl)
+}
+
+// discoverPage returns feeds advertised by the web page b served from
+// pageUrl, falling back to probing common feed locations of the site.
+func (s *Server) discoverPage(b []byte, pageUrl string) ([]parser.Link, error) {
+	links, err := parser.Discover(bytes.NewReader(b), pageUrl)
+	if err != nil {
+		return nil, err
+	} else if len(links) > 0 {
+		return links, nil
+	}
+
+	candidates := make([]*parser.Link, len(commonFeedPaths))
+	var wg sync.WaitGroup
+	for i, path := range commonFeedPaths {
+		wg.Go(func() {
+			if link, _, _, err := s.probe(utils.AbsoluteUrl(path, pageUrl)); err == nil {
+				candidates[i] = link
+			}
+		})
+	}
+	wg.Wait()
+	links = make([]parser.Link, 0)
+	for _, link := range candidates {
+		if link != nil {
+			links = append(links, *link)
+		}
+	}
+	return links, nil
+}
+
+// probe fetches rawUrl and returns it as a link if it is a feed. Otherwise the
+// response body and the URL it was served from after redirects are returned.
+func (s *Server) probe(rawUrl string) (*parser.Link, []byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	req.Header.Set("User-Agent", utils.USER_AGENT)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer resp.Body.Close()
+	if utils.IsErrorResponse(resp.StatusCode) {
+		return nil, nil, "", utils.ResponseError(resp)
+	}
+	b, err := io.ReadAll(decodeBody(resp))
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	finalUrl := resp.Request.URL.String()
+	format := parser.Sniff(b)
+	if format == "" {
+		return nil, b, finalUrl, nil
+	}
+	feed, err := parser.Parse(bytes.NewReader(b), finalUrl)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return &parser.Link{URL: rawUrl, Title: feed.Title, Format: format}, b, finalUrl, nil
+}
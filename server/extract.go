@@ -0,0 +1,61 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"rsslab/parser"
+	"rsslab/storage"
+	"rsslab/utils"
+)
+
+const extractConcurrency = 4
+
+// extractContent downloads the web page at rawUrl and returns its main content.
+func (s *Server) extractContent(rawUrl string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", utils.USER_AGENT)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if utils.IsErrorResponse(resp.StatusCode) {
+		return "", utils.ResponseError(resp)
+	}
+	body, err := parser.DecodeBody(resp, "")
+	if err != nil {
+		return "", err
+	}
+	return parser.ExtractContent(body, resp.Request.URL.String())
+}
+
+// fetchFullContent replaces content of items not stored yet with the main
+// content of their web pages.
+func (s *Server) fetchFullContent(feedId int, items []storage.Item) {
+	g := utils.NewGroup(extractConcurrency)
+	for i := range items {
+		item := &items[i]
+		if item.Link == "" {
+			continue
+		}
+		exists, err := s.db.HasItem(feedId, item.GUID)
+		if err != nil {
+			log.Print(err)
+			return
+		} else if exists {
+			continue
+		}
+		g.Go(func() {
+			content, err := s.extractContent(item.Link)
+			if err != nil {
+				log.Printf("%s: %s", item.Link, err)
+			} else if utils.ExtractText(content) != "" {
+				item.Content = content
+			}
+		})
+	}
+	g.Wait()
+}
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"mime"
 	"net/http"
+	"net/url"
 	"path"
 	"rsslab/parser"
 	"rsslab/storage"
@@ -146,7 +148,30 @@ func (s *Server) handleFeedCreate(c context) error {
 	}
 
 	var state storage.HTTPState
-	rawFeed, err := s.do(body.Url, &state)
+	var values pendingScriptStorage
+	rawFeed, err := s.do(body.Url, &state, &values)
+	var page *errNotFeed
+	if errors.As(err, &page) {
+		// the URL is likely a web page, subscribe to the feed it links to
+		links, derr := s.discoverPage(page.body, page.pageUrl)
+		if derr != nil {
+			return errors.Join(err, derr)
+		}
+		switch len(links) {
+		case 0:
+			return &errBadRequest{errors.New("no feed found at " + body.Url)}
+		case 1:
+			body.Url = links[0].URL
+			state = storage.HTTPState{}
+			rawFeed, err = s.do(body.Url, &state, &values)
+		default:
+			urls := make([]string, len(links))
+			for i, link := range links {
+				urls[i] = link.URL
+			}
+			return &errBadRequest{fmt.Errorf("multiple feeds found at %s: %s", body.Url, strings.Join(urls, ", "))}
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -159,6 +184,12 @@ func (s *Server) handleFeedCreate(c context) error {
 	if err != nil {
 		return err
 	}
+	// the script ran before the feed existed
+	if values.values != nil {
+		if err = s.db.SetScriptValues(feed.Id, values.values); err != nil {
+			return err
+		}
+	}
 	s.setFindingIcon(feed.Id)
 	go s.FindFeedFavicon(*feed)
 
@@ -172,6 +203,67 @@ func (s *Server) handleFeedCreate(c context) error {
 	return c.JSON(feed)
 }
 
+func (s *Server) handleFeedDiscover(c context) error {
+	var params struct {
+		Url string `json:"url"`
+	}
+	if err := c.ParseQuery(&params); err != nil {
+		return err
+	}
+	links, err := s.discover(params.Url)
+	if err != nil {
+		return err
+	}
+	return c.JSON(links)
+}
+
+func (s *Server) handleFeedPreview(c context) error {
+	var params struct {
+		Url string `json:"url"`
+	}
+	if err := c.ParseQuery(&params); err != nil {
+		return err
+	}
+
+	feed, format, err := s.fetch(params.Url, new(storage.HTTPState), 0, nil)
+	if err != nil {
+		return err
+	} else if feed == nil {
+		return c.NotFound()
+	}
+
+	typ := "feed"
+	if u, err := url.Parse(params.Url); err == nil && u.Scheme == "rsslab" {
+		typ = u.Host
+	}
+	var oldest, newest *time.Time
+	var undated int
+	for i := range feed.Items {
+		item := &feed.Items[i]
+		item.Content = sanitize(item.URL, item.Content)
+		if item.Date == nil {
+			undated++
+			continue
+		}
+		if oldest == nil || item.Date.Before(*oldest) {
+			oldest = item.Date
+		}
+		if newest == nil || item.Date.After(*newest) {
+			newest = item.Date
+		}
+	}
+
+	return c.JSON(dict{
+		"feed":       feed,
+		"type":       typ,
+		"format":     format,
+		"item_count": len(feed.Items),
+		"oldest":     oldest,
+		"newest":     newest,
+		"undated":    undated,
+	})
+}
+
 func (s *Server) handleFeedsRefresh(c context) error {
 	go s.RefreshAllFeeds()
 	return nil
@@ -236,16 +328,25 @@ func (s *Server) handleFeedUpdate(c context) error {
 		return err
 	}
 	var body struct {
-		Title    *string `json:"title"`
-		FeedLink *string `json:"feed_link"`
-		FolderId *int    `json:"folder_id"`
+		Title        *string `json:"title"`
+		FeedLink     *string `json:"feed_link"`
+		FolderId     *int    `json:"folder_id"`
+		FetchContent *bool   `json:"fetch_content"`
+		Timezone     *string `json:"timezone"`
 	}
 	if err = c.ParseBody(&body); err != nil {
 		return err
 	}
+	if body.Timezone != nil {
+		if _, err := parser.LoadTimezone(*body.Timezone); err != nil {
+			return &errBadRequest{err}
+		}
+	}
 	editor := storage.FeedEditor{
-		Title:    body.Title,
-		FeedLink: body.FeedLink,
+		Title:        body.Title,
+		FeedLink:     body.FeedLink,
+		FetchContent: body.FetchContent,
+		Timezone:     body.Timezone,
 	}
 	if body.FolderId != nil {
 		if *body.FolderId < 0 {
@@ -323,6 +424,31 @@ func (s *Server) handleItemUpdate(c context) error {
 	return s.db.UpdateItemStatus(id, body.Status)
 }
 
+func (s *Server) handleItemExtract(c context) error {
+	id, err := c.VarInt("id")
+	if err != nil {
+		return err
+	}
+	item, err := s.db.GetItem(id)
+	if err != nil {
+		return err
+	} else if item == nil {
+		return c.NotFound()
+	}
+	content, err := s.extractContent(item.Link)
+	if err != nil {
+		return err
+	}
+	// keep the stored content rather than replacing it with nothing
+	if utils.ExtractText(content) == "" {
+		return &errBadRequest{errors.New("no content could be extracted")}
+	}
+	if err = s.db.UpdateItemContent(id, content); err != nil {
+		return err
+	}
+	return c.JSON(dict{"content": sanitize(item.Link, content)})
+}
+
 func (s *Server) handleSettings(c context) error {
 	settings, err := s.db.GetSettings()
 	if err != nil {
@@ -458,33 +584,78 @@ func (s *Server) handleOPMLExport(c context) error {
 
 func (s *Server) handleTransform(c context) error {
 	typ := c.r.PathValue("type")
+	if c.r.URL.Query().Get("debug") == "true" {
+		return s.handleTransformDebug(c)
+	}
 	var state storage.HTTPState
-	feed, err := s.do("rsslab://"+typ+"?"+c.r.URL.RawQuery, &state)
-	if err != nil {
+	feed, err := s.do("rsslab://"+typ+"?"+c.r.URL.RawQuery, &state, nil)
+	if errors.Is(err, parser.ErrScriptLimit) {
+		return &errBadRequest{err}
+	} else if err != nil {
 		return err
 	}
+	feed.Version = "https://jsonfeed.org/version/1.1"
 	c.w.Header().Set("Content-Type", "application/feed+json; charset=UTF-8")
 	return json.NewEncoder(c.w).Encode(feed)
 }
 
-func (s *Server) proxy(c context) error {
-	var params struct {
-		Url     string            `json:"url"`
-		Headers map[string]string `json:"headers"`
+// handleTransformDebug responds with the feed or error of a rule along with
+// diagnostics collected while applying it.
+func (s *Server) handleTransformDebug(c context) error {
+	url := &url.URL{Scheme: "rsslab", Host: c.r.PathValue("type"), RawQuery: c.r.URL.RawQuery}
+	var d parser.Diagnostics
+	feed, err := s.applyRule(url, new(storage.HTTPState), 0, nil, &d)
+	result := dict{"feed": feed, "diagnostics": &d}
+	if err != nil {
+		result["error"] = err.Error()
 	}
+	return c.JSON(result)
+}
+
+type proxyParams struct {
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	parser.RequestOptions
+}
+
+func (s *Server) proxy(c context) error {
+	var params proxyParams
 	if err := c.ParseQuery(&params); err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodGet, params.Url, nil)
+	req, err := params.NewRequest(params.Url, params.Headers)
 	if err != nil {
+		return &errBadRequest{err}
+	}
+	// the proxy is reachable by plain links, don't let them modify resources
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return &errBadRequest{fmt.Errorf("method %s not allowed, use POST /api/proxy", req.Method)}
+	}
+	return s.forward(c, req)
+}
+
+// proxyPost is like proxy for requests with any method. The parameters come
+// in a JSON body, which cross-site forms cannot send.
+func (s *Server) proxyPost(c context) error {
+	if t, _, _ := mime.ParseMediaType(c.r.Header.Get("Content-Type")); t != "application/json" {
+		return &errBadRequest{errors.New("content type must be application/json")}
+	}
+	var params proxyParams
+	if err := c.ParseBody(&params); err != nil {
 		return err
 	}
-	req.Header.Set("User-Agent", utils.USER_AGENT)
-	for k, v := range params.Headers {
-		req.Header.Set(k, v)
+
+	req, err := params.NewRequest(params.Url, params.Headers)
+	if err != nil {
+		return &errBadRequest{err}
 	}
+	return s.forward(c, req)
+}
 
+// forward responds with the body of req's response, as plain text unless it
+// is JSON.
+func (s *Server) forward(c context, req *http.Request) error {
 	resp, err := s.client.Do(req)
 	if err != nil {
 		return err
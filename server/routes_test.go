@@ -0,0 +1,199 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"rsslab/storage"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T) *Server {
+	db, err := storage.New(filepath.Join(t.TempDir(), "storage.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Server{db: db, iconFinder: make(map[int]chan struct{})}
+}
+
+func TestFeedCreateDiscover(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/one":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<link rel="alternate" type="application/rss+xml" href="/feeds/a.xml">`)
+		case "/many":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `
+				<link rel="alternate" type="application/rss+xml" href="/feeds/a.xml">
+				<link rel="alternate" type="application/atom+xml" href="/feeds/b.xml">
+			`)
+		case "/none":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<p>No feeds</p>`)
+		case "/feeds/a.xml":
+			fmt.Fprint(w, `<rss version="2.0"><channel><title>A</title><item><title>Item</title></item></channel></rss>`)
+		case "/feeds/b.xml":
+			fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"><title>B</title></feed>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	testcases := []struct {
+		path   string
+		status int
+		want   string
+	}{
+		{"/one", http.StatusOK, site.URL + "/feeds/a.xml"},
+		{"/many", http.StatusBadRequest, fmt.Sprintf("multiple feeds found at %s/many: %s/feeds/a.xml, %s/feeds/b.xml", site.URL, site.URL, site.URL)},
+		{"/none", http.StatusBadRequest, fmt.Sprintf("no feed found at %s/none", site.URL)},
+	}
+	s := newTestServer(t)
+	for _, testcase := range testcases {
+		body := fmt.Sprintf(`{"url": %q}`, site.URL+testcase.path)
+		w := httptest.NewRecorder()
+		wrap(s.handleFeedCreate)(w, httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(body)))
+		if w.Code != testcase.status {
+			t.Fatalf("%s\nwant: %d\nhave: %d %s", testcase.path, testcase.status, w.Code, w.Body)
+		}
+		have := w.Body.String()
+		if w.Code == http.StatusOK {
+			var feed storage.Feed
+			if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+				t.Fatal(err)
+			}
+			have = feed.FeedLink
+		}
+		if have != testcase.want {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.path, testcase.want, have)
+		}
+		// the page is not downloaded again for discovery
+		mu.Lock()
+		n := requests[testcase.path]
+		mu.Unlock()
+		if n != 1 {
+			t.Fatalf("%s: want 1 request, have %d", testcase.path, n)
+		}
+	}
+}
+
+func TestItemExtractNoContent(t *testing.T) {
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><nav><a href="/">Home</a></nav></body></html>`)
+	}))
+	defer page.Close()
+
+	s := newTestServer(t)
+	feed, err := s.db.CreateFeed("Feed", "", page.URL+"/feed.xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []storage.Item{{GUID: "1", FeedId: feed.Id, Link: page.URL, Content: "<p>Summary</p>"}}
+	if err := s.db.CreateItems(items, feed.Id, time.Now(), &storage.HTTPState{}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/items/1/extract", nil)
+	r.SetPathValue("id", "1")
+	wrap(s.handleItemExtract)(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("want: %d\nhave: %d %s", http.StatusBadRequest, w.Code, w.Body)
+	}
+	item, err := s.db.GetItem(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the stored content is kept
+	if want, have := "<p>Summary</p>", item.Content; want != have {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestFeedCreateScriptValues(t *testing.T) {
+	script := `storage.set('seen', ['1']); module.exports = {title: 'Script', items: [{id: '1', title: 'One'}]}`
+	feedUrl := "rsslab://js?" + url.Values{"script": {script}}.Encode()
+
+	s := newTestServer(t)
+	w := httptest.NewRecorder()
+	body := fmt.Sprintf(`{"url": %q}`, feedUrl)
+	wrap(s.handleFeedCreate)(w, httptest.NewRequest(http.MethodPost, "/api/feeds", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("want: %d\nhave: %d %s", http.StatusOK, w.Code, w.Body)
+	}
+	var feed storage.Feed
+	if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatal(err)
+	}
+	// values set before the feed existed are kept
+	have, err := s.db.GetScriptValues(feed.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"seen": `["1"]`}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("want: %#v\nhave: %#v", want, have)
+	}
+}
+
+func TestProxyMethods(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer site.Close()
+
+	params := url.Values{"url": {site.URL}, "method": {"POST"}, "body": {"a=1"}}
+	body := fmt.Sprintf(`{"url": %q, "method": "POST", "body": "a=1"}`, site.URL)
+	testcases := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		want        string
+	}{
+		{"GET", http.MethodGet, "/api/proxy?url=" + url.QueryEscape(site.URL), "", "", http.StatusOK, "GET "},
+		// plain links must not modify resources
+		{"GET overriding method", http.MethodGet, "/api/proxy?" + params.Encode(), "", "", http.StatusBadRequest, ""},
+		{"POST", http.MethodPost, "/api/proxy", "application/json", body, http.StatusOK, "POST a=1"},
+		// cross-site forms cannot send JSON
+		{"POST form", http.MethodPost, "/api/proxy", "text/plain", body, http.StatusBadRequest, ""},
+	}
+	s := newTestServer(t)
+	for _, testcase := range testcases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(testcase.method, testcase.target, strings.NewReader(testcase.body))
+		if testcase.contentType != "" {
+			r.Header.Set("Content-Type", testcase.contentType)
+		}
+		handler := s.proxy
+		if testcase.method == http.MethodPost {
+			handler = s.proxyPost
+		}
+		wrap(handler)(w, r)
+		if w.Code != testcase.status {
+			t.Fatalf("%s\nwant: %d\nhave: %d %s", testcase.name, testcase.status, w.Code, w.Body)
+		}
+		if have := w.Body.String(); testcase.status == http.StatusOK && have != testcase.want {
+			t.Fatalf("%s\nwant: %#v\nhave: %#v", testcase.name, testcase.want, have)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"cmp"
 	"compress/gzip"
 	"errors"
@@ -116,6 +117,8 @@ func (s *Server) Start(addr string) error {
 	mux.HandleFunc("POST   /api/folders/{id}/refresh", wrap(s.handleFolderRefresh))
 	mux.HandleFunc("GET    /api/feeds", wrap(s.handleFeedList))
 	mux.HandleFunc("POST   /api/feeds", wrap(s.handleFeedCreate))
+	mux.HandleFunc("GET    /api/feeds/discover", wrap(s.handleFeedDiscover))
+	mux.HandleFunc("GET    /api/feeds/preview", wrap(s.handleFeedPreview))
 	mux.HandleFunc("POST   /api/feeds/refresh", wrap(s.handleFeedsRefresh))
 	mux.HandleFunc("GET    /api/feeds/{id}/has_icon", wrap(s.handleFeedHasIcon))
 	mux.HandleFunc("GET    /api/feeds/{id}/icon", wrap(s.handleFeedIcon))
@@ -126,12 +129,14 @@ func (s *Server) Start(addr string) error {
 	mux.HandleFunc("PUT    /api/items", wrap(s.handleItemRead))
 	mux.HandleFunc("GET    /api/items/{id}", wrap(s.handleItem))
 	mux.HandleFunc("PUT    /api/items/{id}", wrap(s.handleItemUpdate))
+	mux.HandleFunc("POST   /api/items/{id}/extract", wrap(s.handleItemExtract))
 	mux.HandleFunc("GET    /api/settings", wrap(s.handleSettings))
 	mux.HandleFunc("PUT    /api/settings", wrap(s.handleSettingsUpdate))
 	mux.HandleFunc("POST   /api/opml/import", wrap(s.handleOPMLImport))
 	mux.HandleFunc("GET    /api/opml/export", wrap(s.handleOPMLExport))
 	mux.HandleFunc("GET    /api/transform/{type}", wrap(s.handleTransform))
 	mux.HandleFunc("GET    /api/proxy", wrap(s.proxy))
+	mux.HandleFunc("POST   /api/proxy", wrap(s.proxyPost))
 	mux.HandleFunc("GET    /", s.handleIndex)
 
 	host, port := addr, ""
@@ -256,42 +261,29 @@ func (s *Server) RefreshFeeds(feeds ...storage.Feed) {
 	}
 }
 
-func (s *Server) do(rawUrl string, state *storage.HTTPState) (*parser.Feed, error) {
+// do fetches a feed that is not stored. Values set by scripts are kept in
+// values if not nil.
+func (s *Server) do(rawUrl string, state *storage.HTTPState, values parser.ScriptStorage) (*parser.Feed, error) {
+	feed, _, err := s.fetch(rawUrl, state, 0, values)
+	return feed, err
+}
+
+// fetch is like do but also returns the detected format of plain feeds.
+// Unless feedId is 0, detail pages of scraped items are cached and values of
+// scripts are kept for the feed instead.
+func (s *Server) fetch(rawUrl string, state *storage.HTTPState, feedId int, values parser.ScriptStorage) (*parser.Feed, string, error) {
 	url, err := url.Parse(rawUrl)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if url.Scheme == "rsslab" {
-		switch url.Host {
-		case "html":
-			rule := new(parser.HTMLRule)
-			if err := utils.ParseQuery(url, rule); err != nil {
-				return nil, err
-			}
-			return rule.Apply(&s.client)
-
-		case "json":
-			rule := new(parser.JSONRule)
-			if err := utils.ParseQuery(url, rule); err != nil {
-				return nil, err
-			}
-			return rule.Apply(&s.client)
-
-		case "js":
-			rule := new(parser.JavaScriptRule)
-			if err := utils.ParseQuery(url, rule); err != nil {
-				return nil, err
-			}
-			return rule.Apply(&s.client)
-
-		default:
-			return nil, errors.New("invalid URL")
-		}
+		feed, err := s.applyRule(url, state, feedId, values, nil)
+		return feed, "", err
 	}
 
 	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if state != nil {
 		if state.LastModified != nil {
@@ -309,11 +301,11 @@ func (s *Server) do(rawUrl string, state *storage.HTTPState) (*parser.Feed, erro
 		err = utils.ResponseError(resp)
 	}
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusNotModified {
-		return nil, nil
+		return nil, "", nil
 	}
 
 	lmod := resp.Header.Get("Last-Modified")
@@ -323,6 +315,73 @@ func (s *Server) do(rawUrl string, state *storage.HTTPState) (*parser.Feed, erro
 		state.Etag = &etag
 	}
 
+	b, err := io.ReadAll(decodeBody(resp))
+	if err != nil {
+		return nil, "", err
+	}
+	feed, format, err := parser.ParseFormat(bytes.NewReader(b), rawUrl)
+	if errors.Is(err, parser.ErrUnknownFormat) {
+		err = &errNotFeed{err, b, resp.Request.URL.String()}
+	}
+	return feed, format, err
+}
+
+// errNotFeed is returned by fetch for responses in an unknown format. It
+// keeps the body, so feeds linked by a web page can be discovered without
+// downloading it again.
+type errNotFeed struct {
+	err     error
+	body    []byte
+	pageUrl string
+}
+
+func (err *errNotFeed) Error() string {
+	return err.err.Error()
+}
+
+func (err *errNotFeed) Unwrap() error {
+	return err.err
+}
+
+// applyRule applies the rule in a rsslab:// URL. See fetch for feedId and
+// values. Diagnostics are recorded in d if not nil.
+func (s *Server) applyRule(url *url.URL, state *storage.HTTPState, feedId int, values parser.ScriptStorage, d *parser.Diagnostics) (*parser.Feed, error) {
+	var feed *parser.Feed
+	var err error
+	var cache parser.DetailCache
+	if feedId != 0 {
+		cache = &detailCache{s.db, feedId}
+		values = &scriptStorage{s.db, feedId}
+	}
+	switch url.Host {
+	case "html":
+		rule := &parser.HTMLRule{DetailRule: parser.DetailRule{Cache: cache}, Diagnostics: d}
+		if err = utils.ParseQuery(url, rule); err == nil {
+			feed, err = rule.Apply(&s.client)
+		}
+
+	case "json":
+		rule := &parser.JSONRule{DetailRule: parser.DetailRule{Cache: cache}, Diagnostics: d}
+		if err = utils.ParseQuery(url, rule); err == nil {
+			feed, err = rule.Apply(&s.client)
+		}
+
+	case "js":
+		rule := &parser.JavaScriptRule{Storage: values, Diagnostics: d}
+		if state != nil {
+			rule.LastRefreshed = state.LastRefreshed
+		}
+		if err = utils.ParseQuery(url, rule); err == nil {
+			feed, err = rule.Apply(&s.client)
+		}
+
+	default:
+		err = errors.New("invalid URL")
+	}
+	return feed, err
+}
+
+func decodeBody(resp *http.Response) io.Reader {
 	var b io.Reader = resp.Body
 	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
 		if cs, ok := params["charset"]; ok {
@@ -331,7 +390,7 @@ func (s *Server) do(rawUrl string, state *storage.HTTPState) (*parser.Feed, erro
 			}
 		}
 	}
-	return parser.Parse(b, rawUrl)
+	return b
 }
 
 func (s *Server) worker() {
@@ -353,11 +412,23 @@ func (s *Server) listItems(f storage.Feed) ([]storage.Item, *storage.HTTPState,
 	if err != nil {
 		return nil, nil, err
 	}
-	feed, err := s.do(f.FeedLink, &state)
+	feed, format, err := s.fetch(f.FeedLink, &state, f.Id, nil)
 	if err != nil || feed == nil {
 		return nil, nil, err
 	}
-	return convertItems(feed.Items, f), &state, nil
+	if format != "" && f.Timezone != "" {
+		// rules have their own time zone settings
+		loc, err := parser.LoadTimezone(f.Timezone)
+		if err != nil {
+			return nil, nil, err
+		}
+		feed.SetDefaultTimezone(loc)
+	}
+	items := convertItems(feed.Items, f)
+	if f.FetchContent {
+		s.fetchFullContent(f.Id, items)
+	}
+	return items, &state, nil
 }
 
 func (s *Server) setFindingIcon(feedId int) {
@@ -371,21 +442,100 @@ func convertItems(items []parser.Item, feed storage.Feed) []storage.Item {
 	now := time.Now()
 	for i, item := range items {
 		result[i] = storage.Item{
-			GUID:    cmp.Or(item.GUID, item.URL),
-			FeedId:  feed.Id,
-			Title:   item.Title,
-			Link:    item.URL,
-			Content: item.Content,
-			Status:  storage.UNREAD,
+			GUID:       cmp.Or(item.GUID, item.URL),
+			FeedId:     feed.Id,
+			Title:      item.Title,
+			Link:       item.URL,
+			Content:    item.Content,
+			Status:     storage.UNREAD,
+			Categories: item.Tags,
 		}
 		if item.Date == nil {
 			result[i].Date = now
 		} else {
 			result[i].Date = *item.Date
 		}
+		if author := item.Author(); author != "" {
+			result[i].Author = &author
+		}
+		if item.ImageURL == "" {
+			if src := utils.FirstImage(item.Content); src != "" {
+				item.ImageURL = utils.AbsoluteUrl(src, item.URL)
+			}
+		}
 		if item.ImageURL != "" {
 			result[i].ImageURL = &item.ImageURL
 		}
+		if item.AudioURL != "" {
+			result[i].AudioURL = &item.AudioURL
+		}
+		if item.AudioDuration > 0 {
+			result[i].AudioDuration = &item.AudioDuration
+		}
+		if item.ChaptersURL != "" {
+			result[i].ChaptersURL = &item.ChaptersURL
+		}
+		if item.TranscriptURL != "" {
+			result[i].TranscriptURL = &item.TranscriptURL
+		}
 	}
 	return result
 }
+
+// detailCache stores detail pages of scraped items of a feed.
+type detailCache struct {
+	db     *storage.Storage
+	feedId int
+}
+
+func (c *detailCache) GetDetail(guid string) (*parser.Detail, error) {
+	d, err := c.db.GetItemDetail(c.feedId, guid)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	detail := &parser.Detail{Date: d.Date}
+	if d.Content != nil {
+		detail.Content = *d.Content
+	}
+	if d.ImageURL != nil {
+		detail.ImageURL = *d.ImageURL
+	}
+	return detail, nil
+}
+
+func (c *detailCache) SetDetail(guid string, detail *parser.Detail) error {
+	return c.db.SetItemDetail(c.feedId, guid, storage.ItemDetail{
+		Content:  &detail.Content,
+		Date:     detail.Date,
+		ImageURL: &detail.ImageURL,
+	})
+}
+
+// scriptStorage stores values set by the script of a feed.
+type scriptStorage struct {
+	db     *storage.Storage
+	feedId int
+}
+
+func (s *scriptStorage) LoadScriptValues() (map[string]string, error) {
+	return s.db.GetScriptValues(s.feedId)
+}
+
+func (s *scriptStorage) SaveScriptValues(values map[string]string) error {
+	return s.db.SetScriptValues(s.feedId, values)
+}
+
+// pendingScriptStorage holds values set by the script of a feed that is not
+// stored yet.
+type pendingScriptStorage struct {
+	values map[string]string
+}
+
+func (s *pendingScriptStorage) LoadScriptValues() (map[string]string, error) {
+	return nil, nil
+}
+
+func (s *pendingScriptStorage) SaveScriptValues(values map[string]string) error {
+	s.values = values
+	return nil
+}
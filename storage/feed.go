@@ -15,6 +15,8 @@ type Feed struct {
 	Link          string     `json:"link,omitempty"`
 	FeedLink      string     `json:"feed_link"`
 	HasIcon       bool       `json:"has_icon"`
+	FetchContent  bool       `json:"fetch_content"`
+	Timezone      string     `json:"timezone"` // default for dates without time zone
 	LastRefreshed *time.Time `json:"last_refreshed,omitempty"`
 }
 
@@ -54,6 +56,9 @@ type FeedEditor struct {
 	Title    *string `json:"title"`
 	FeedLink *string `json:"feed_link"`
 	FolderId **int   `json:"folder_id"`
+
+	FetchContent *bool   `json:"fetch_content"`
+	Timezone     *string `json:"timezone"`
 }
 
 func (s *Storage) EditFeed(feedId int, editor FeedEditor) error {
@@ -71,6 +76,14 @@ func (s *Storage) EditFeed(feedId int, editor FeedEditor) error {
 		acts = append(acts, "folder_id = ?")
 		args = append(args, *editor.FolderId)
 	}
+	if editor.FetchContent != nil {
+		acts = append(acts, "fetch_content = ?")
+		args = append(args, *editor.FetchContent)
+	}
+	if editor.Timezone != nil {
+		acts = append(acts, "timezone = ?")
+		args = append(args, *editor.Timezone)
+	}
 	if len(acts) == 0 {
 		return nil
 	}
@@ -93,7 +106,7 @@ func (s *Storage) ListFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
 		select
 			id, folder_id, title, link, feed_link,
-			icon is not null as has_icon
+			icon is not null as has_icon, fetch_content, timezone
 		from feeds
 		order by title collate nocase
 	`)
@@ -110,6 +123,8 @@ func (s *Storage) ListFeeds() ([]Feed, error) {
 			&f.Link,
 			&f.FeedLink,
 			&f.HasIcon,
+			&f.FetchContent,
+			&f.Timezone,
 		)
 		if err != nil {
 			return nil, newError(err)
@@ -154,9 +169,9 @@ func (s *Storage) ListFeedsMissingIcons() (feeds []Feed) {
 func (s *Storage) GetFeed(id int) (*Feed, error) {
 	var f Feed
 	err := s.db.QueryRow(`
-		select id, link, feed_link
+		select id, link, feed_link, fetch_content, timezone
 		from feeds where id = ?
-	`, id).Scan(&f.Id, &f.Link, &f.FeedLink)
+	`, id).Scan(&f.Id, &f.Link, &f.FeedLink, &f.FetchContent, &f.Timezone)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -192,7 +207,7 @@ func (s *Storage) GetFeedIcon(id int) ([]byte, error) {
 
 func (s *Storage) GetFeeds(folderId int) ([]Feed, error) {
 	rows, err := s.db.Query(`
-		select id, feed_link
+		select id, feed_link, fetch_content, timezone
 		from feeds
 		where folder_id = ?
 		order by title collate nocase
@@ -203,7 +218,7 @@ func (s *Storage) GetFeeds(folderId int) ([]Feed, error) {
 	result := make([]Feed, 0)
 	for rows.Next() {
 		var f Feed
-		err = rows.Scan(&f.Id, &f.FeedLink)
+		err = rows.Scan(&f.Id, &f.FeedLink, &f.FetchContent, &f.Timezone)
 		if err != nil {
 			return nil, newError(err)
 		}
@@ -232,15 +247,19 @@ func (s *Storage) SetFeedError(feedId int, lastError error) {
 type HTTPState struct {
 	LastModified *string
 	Etag         *string
+
+	// LastRefreshed is only read; CreateItems updates it separately.
+	LastRefreshed *time.Time
 }
 
 func (s *Storage) GetHTTPState(feedId int) (state HTTPState, err error) {
 	err = s.db.QueryRow(`
-		select last_modified, etag
+		select last_modified, etag, last_refreshed
 		from feeds where id = ?
 	`, feedId).Scan(
 		&state.LastModified,
 		&state.Etag,
+		&state.LastRefreshed,
 	)
 	if err != nil {
 		err = newError(err)
@@ -303,3 +322,52 @@ func (s *Storage) FeedState() (map[int]FeedState, error) {
 
 	return result, nil
 }
+
+func (s *Storage) GetScriptValues(feedId int) (map[string]string, error) {
+	rows, err := s.db.Query(`
+		select key, value from script_values where feed_id = ?
+	`, feedId)
+	if err != nil {
+		return nil, newError(err)
+	}
+	values := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err = rows.Scan(&key, &value); err != nil {
+			return nil, newError(err)
+		}
+		values[key] = value
+	}
+	if err = rows.Err(); err != nil {
+		return nil, newError(err)
+	}
+	return values, nil
+}
+
+// SetScriptValues replaces all script values of a feed.
+func (s *Storage) SetScriptValues(feedId int, values map[string]string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return newError(err)
+	}
+	_, err = tx.Exec(`delete from script_values where feed_id = ?`, feedId)
+	for key, value := range values {
+		if err != nil {
+			break
+		}
+		_, err = tx.Exec(`
+			insert into script_values (feed_id, key, value)
+			values (?, ?, ?)
+		`, feedId, key, value)
+	}
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Print(err)
+		}
+		return newError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return newError(err)
+	}
+	return nil
+}
@@ -60,16 +60,21 @@ func (s *ItemStatus) UnmarshalText(text []byte) error {
 }
 
 type Item struct {
-	Id       int        `json:"id"`
-	GUID     string     `json:"guid"`
-	FeedId   int        `json:"feed_id"`
-	Title    string     `json:"title"`
-	Link     string     `json:"link"`
-	Content  string     `json:"content"`
-	Date     time.Time  `json:"date"`
-	Status   ItemStatus `json:"status"`
-	ImageURL *string    `json:"image,omitempty"`
-	AudioURL *string    `json:"podcast_url,omitempty"`
+	Id            int        `json:"id"`
+	GUID          string     `json:"guid"`
+	FeedId        int        `json:"feed_id"`
+	Title         string     `json:"title"`
+	Link          string     `json:"link"`
+	Author        *string    `json:"author,omitempty"`
+	Content       string     `json:"content"`
+	Date          time.Time  `json:"date"`
+	Status        ItemStatus `json:"status"`
+	ImageURL      *string    `json:"image,omitempty"`
+	AudioURL      *string    `json:"podcast_url,omitempty"`
+	AudioDuration *int       `json:"podcast_duration,omitempty"`
+	ChaptersURL   *string    `json:"podcast_chapters,omitempty"`
+	TranscriptURL *string    `json:"podcast_transcript,omitempty"`
+	Categories    []string   `json:"categories,omitempty"`
 }
 
 func (s *Storage) CreateItems(items []Item, feedId int, lastRefreshed time.Time, state *HTTPState) error {
@@ -84,18 +89,28 @@ func (s *Storage) CreateItems(items []Item, feedId int, lastRefreshed time.Time,
 	lastRefreshed = lastRefreshed.UTC()
 	for i := len(items) - 1; i >= 0; i-- {
 		item := items[i]
-		_, err := tx.Exec(`
+		var id int
+		err := tx.QueryRow(`
 			insert into items (
-				guid, feed_id, title, link, date,
+				guid, feed_id, title, link, author, date,
 				content, content_text, image,
-				podcast_url, date_arrived, status
+				podcast_url, podcast_duration, podcast_chapters,
+				podcast_transcript, date_arrived, status
 			)
-			values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-			on conflict (feed_id, guid) do nothing`,
-			item.GUID, item.FeedId, item.Title, item.Link, item.Date.UTC(),
+			values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			on conflict (feed_id, guid) do nothing
+			returning id`,
+			item.GUID, item.FeedId, item.Title, item.Link, item.Author, item.Date.UTC(),
 			item.Content, utils.ExtractText(item.Content), item.ImageURL,
-			item.AudioURL, lastRefreshed, UNREAD,
-		)
+			item.AudioURL, item.AudioDuration, item.ChaptersURL,
+			item.TranscriptURL, lastRefreshed, UNREAD,
+		).Scan(&id)
+		if err == sql.ErrNoRows {
+			// item already exists
+			continue
+		} else if err == nil {
+			err = createItemCategories(tx, id, item.Categories)
+		}
 		if err != nil {
 			if err := tx.Rollback(); err != nil {
 				log.Print(err)
@@ -135,11 +150,27 @@ func (s *Storage) CreateItems(items []Item, feedId int, lastRefreshed time.Time,
 	return nil
 }
 
+func createItemCategories(tx *sql.Tx, itemId int, categories []string) error {
+	for _, category := range categories {
+		_, err := tx.Exec(`
+			insert or ignore into item_categories (item_id, category)
+			values (?, ?)`,
+			itemId, category,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type ItemFilter struct {
 	FolderId    *int        `json:"folder_id"`
 	FeedId      *int        `json:"feed_id"`
 	Status      *ItemStatus `json:"status"`
 	Search      *string     `json:"search"`
+	Author      *string     `json:"author"`
+	Category    *string     `json:"category"`
 	After       *int        `json:"after"`
 	OldestFirst bool        `json:"oldest_first"`
 }
@@ -162,10 +193,18 @@ func listQueryPredicate(filter ItemFilter, includeBoundary bool) (string, []any)
 	if filter.Search != nil {
 		for word := range strings.FieldsSeq(*filter.Search) {
 			word = "%" + word + "%"
-			cond = append(cond, "(title like ? or content_text like ?)")
-			args = append(args, word, word)
+			cond = append(cond, "(title like ? or content_text like ? or author like ?)")
+			args = append(args, word, word, word)
 		}
 	}
+	if filter.Author != nil {
+		cond = append(cond, "author like ?")
+		args = append(args, "%"+*filter.Author+"%")
+	}
+	if filter.Category != nil {
+		cond = append(cond, "id in (select item_id from item_categories where category = ?)")
+		args = append(args, *filter.Category)
+	}
 	if filter.After != nil {
 		compare := "<"
 		if filter.OldestFirst {
@@ -185,6 +224,12 @@ func listQueryPredicate(filter ItemFilter, includeBoundary bool) (string, []any)
 	return predicate, args
 }
 
+const categoriesColumn = `(
+	select json_group_array(category)
+	from item_categories
+	where item_id = items.id
+)`
+
 func (s *Storage) ListItems(filter ItemFilter, limit int) ([]Item, error) {
 	predicate, args := listQueryPredicate(filter, false)
 	order := "date desc, id desc"
@@ -194,23 +239,29 @@ func (s *Storage) ListItems(filter ItemFilter, limit int) ([]Item, error) {
 	rows, err := s.db.Query(fmt.Sprintf(`
 		select
 			id, guid, feed_id, iif(title = '', content, title),
-			link, date, status, image, podcast_url
+			link, author, date, status, image, podcast_url,
+			podcast_duration, %s
 		from items
 		where %s
 		order by %s
 		limit %d
-	`, predicate, order, limit), args...)
+	`, categoriesColumn, predicate, order, limit), args...)
 	if err != nil {
 		return nil, newError(err)
 	}
 	result := make([]Item, 0)
 	for rows.Next() {
 		var i Item
+		var categories string
 		err = rows.Scan(
 			&i.Id, &i.GUID, &i.FeedId,
-			&i.Title, &i.Link, &i.Date,
-			&i.Status, &i.ImageURL, &i.AudioURL,
+			&i.Title, &i.Link, &i.Author, &i.Date,
+			&i.Status, &i.ImageURL, &i.AudioURL, &i.AudioDuration,
+			&categories,
 		)
+		if err == nil {
+			err = json.Unmarshal(utils.StringToBytes(categories), &i.Categories)
+		}
 		if err != nil {
 			return nil, newError(err)
 		}
@@ -224,16 +275,22 @@ func (s *Storage) ListItems(filter ItemFilter, limit int) ([]Item, error) {
 
 func (s *Storage) GetItem(id int) (*Item, error) {
 	var i Item
-	err := s.db.QueryRow(`
+	var categories string
+	err := s.db.QueryRow(fmt.Sprintf(`
 		select
-			id, guid, feed_id, title, link, content,
-			date, status, image, podcast_url
+			id, guid, feed_id, title, link, author, content,
+			date, status, image, podcast_url, podcast_duration,
+			podcast_chapters, podcast_transcript, %s
 		from items
 		where id = ?
-	`, id).Scan(
-		&i.Id, &i.GUID, &i.FeedId, &i.Title, &i.Link, &i.Content,
-		&i.Date, &i.Status, &i.ImageURL, &i.AudioURL,
+	`, categoriesColumn), id).Scan(
+		&i.Id, &i.GUID, &i.FeedId, &i.Title, &i.Link, &i.Author, &i.Content,
+		&i.Date, &i.Status, &i.ImageURL, &i.AudioURL, &i.AudioDuration,
+		&i.ChaptersURL, &i.TranscriptURL, &categories,
 	)
+	if err == nil {
+		err = json.Unmarshal(utils.StringToBytes(categories), &i.Categories)
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -243,6 +300,69 @@ func (s *Storage) GetItem(id int) (*Item, error) {
 	return &i, nil
 }
 
+// ItemDetail holds fields extracted from the page of a scraped item.
+type ItemDetail struct {
+	Content  *string
+	Date     *time.Time
+	ImageURL *string
+}
+
+func (s *Storage) GetItemDetail(feedId int, guid string) (*ItemDetail, error) {
+	var d ItemDetail
+	err := s.db.QueryRow(`
+		select content, date, image
+		from item_details
+		where feed_id = ? and guid = ?
+	`, feedId, guid).Scan(&d.Content, &d.Date, &d.ImageURL)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, newError(err)
+	}
+	return &d, nil
+}
+
+func (s *Storage) SetItemDetail(feedId int, guid string, detail ItemDetail) error {
+	var date *time.Time
+	if detail.Date != nil {
+		utc := detail.Date.UTC()
+		date = &utc
+	}
+	_, err := s.db.Exec(`
+		insert or replace into item_details (
+			feed_id, guid, content, date, image, date_fetched
+		)
+		values (?, ?, ?, ?, ?, ?)
+	`, feedId, guid, detail.Content, date, detail.ImageURL, time.Now().UTC())
+	if err != nil {
+		return newError(err)
+	}
+	return nil
+}
+
+func (s *Storage) HasItem(feedId int, guid string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`
+		select exists (select 1 from items where feed_id = ? and guid = ?)
+	`, feedId, guid).Scan(&exists)
+	if err != nil {
+		return false, newError(err)
+	}
+	return exists, nil
+}
+
+func (s *Storage) UpdateItemContent(itemId int, content string) error {
+	_, err := s.db.Exec(`
+		update items set content = ?, content_text = ?
+		where id = ?
+	`, content, utils.ExtractText(content), itemId)
+	if err != nil {
+		return newError(err)
+	}
+	return nil
+}
+
 func (s *Storage) UpdateItemStatus(itemId int, status ItemStatus) error {
 	_, err := s.db.Exec(`update items set status = ? where id = ?`, status, itemId)
 	if err != nil {
@@ -328,4 +448,15 @@ func (s *Storage) DeleteOldItems() {
 			log.Printf("deleted %d old items (feed: %d)", numDeleted, feedId)
 		}
 	}
+
+	_, err = s.db.Exec(`
+		delete from item_details
+		where date_fetched < ? and not exists (
+			select 1 from items
+			where items.feed_id = item_details.feed_id and items.guid = item_details.guid
+		)
+	`, dateArrived)
+	if err != nil {
+		log.Print(err)
+	}
 }
@@ -111,4 +111,65 @@ var migrations = []func(*sql.Tx) error{
 		_, err := tx.Exec(`alter table feeds add column icon blob`)
 		return err
 	},
+	func(tx *sql.Tx) error {
+		sql := `
+			alter table items add column podcast_duration integer;
+			alter table items add column podcast_chapters text;
+			alter table items add column podcast_transcript text;
+		`
+		_, err := tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`alter table items add column author text`)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		sql := `
+			create table item_categories (
+			 item_id        references items(id) on delete cascade,
+			 category       text not null collate nocase,
+			 primary key (item_id, category)
+			);
+
+			create index idx_item_category on item_categories(category);
+		`
+		_, err := tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		sql := `
+			create table item_details (
+			 feed_id        references feeds(id) on delete cascade,
+			 guid           text not null,
+			 content        text,
+			 date           datetime,
+			 image          text,
+			 date_fetched   datetime not null,
+			 primary key (feed_id, guid)
+			);
+		`
+		_, err := tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`alter table feeds add column fetch_content integer not null default 0`)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		sql := `
+			create table script_values (
+			 feed_id        references feeds(id) on delete cascade,
+			 key            text not null,
+			 value          text not null,
+			 primary key (feed_id, key)
+			);
+		`
+		_, err := tx.Exec(sql)
+		return err
+	},
+	func(tx *sql.Tx) error {
+		_, err := tx.Exec(`alter table feeds add column timezone text not null default ''`)
+		return err
+	},
 }
@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql"
 	"log"
+	"strings"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -12,7 +13,14 @@ type Storage struct {
 }
 
 func New(path string) (*Storage, error) {
-	db, err := sql.Open("sqlite3", path)
+	// cascading deletes of items, categories, details and script values
+	// need foreign keys, which are off by default without the
+	// sqlite_foreign_keys build tag
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	db, err := sql.Open("sqlite3", path+sep+"_foreign_keys=on")
 	if err != nil {
 		return nil, err
 	}
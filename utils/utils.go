@@ -13,14 +13,38 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"unsafe"
 
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 	"golang.org/x/net/html/charset"
 )
 
 const USER_AGENT = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36"
 
+// Group runs functions concurrently, at most limit of them at a time.
+type Group struct {
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+func NewGroup(limit int) *Group {
+	return &Group{sem: make(chan struct{}, limit)}
+}
+
+func (g *Group) Go(f func()) {
+	g.wg.Go(func() {
+		g.sem <- struct{}{}
+		defer func() { <-g.sem }()
+		f()
+	})
+}
+
+func (g *Group) Wait() {
+	g.wg.Wait()
+}
+
 func BytesToString(b []byte) string {
 	return *(*string)(unsafe.Pointer(&b))
 }
@@ -70,6 +94,30 @@ func ExtractText(content string) string {
 	return CollapseWhitespace(b.String())
 }
 
+// FirstImage returns the source of the first non-inline <img> in content.
+func FirstImage(content string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(content))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.DataAtom != atom.Img {
+				continue
+			}
+			for _, attr := range token.Attr {
+				if attr.Key == "src" {
+					if src := strings.TrimSpace(attr.Val); src != "" && !strings.HasPrefix(src, "data:") {
+						return src
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
 func ResponseError(resp *http.Response) error {
 	return fmt.Errorf(`%s "%s": %s`, resp.Request.Method, resp.Request.URL, resp.Status)
 }
@@ -95,7 +143,7 @@ func ParseQuery(url *url.URL, v any) error {
 				if err := ParseQuery(url, f.Addr().Interface()); err != nil {
 					return err
 				}
-			} else if key, ok := typ.Field(i).Tag.Lookup("json"); ok {
+			} else if key, ok := typ.Field(i).Tag.Lookup("json"); ok && key != "-" {
 				if v := q.Get(key); v != "" {
 					if k == reflect.Pointer && f.IsZero() {
 						f.Set(reflect.New(f.Type().Elem()))
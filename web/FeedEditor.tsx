@@ -77,14 +77,36 @@ export default function FeedEditor({
 
   const [transHtmlUrl, setTransHtmlUrl] = useState('')
   const [transHtmlHeaders, setTransHtmlHeaders] = useState('')
+  const [transHtmlMethod, setTransHtmlMethod] = useState('')
+  const [transHtmlBody, setTransHtmlBody] = useState('')
+  const [transHtmlContentType, setTransHtmlContentType] = useState('')
+  const [transHtmlSelectorType, setTransHtmlSelectorType] = useState('')
   const [transHtmlTitle, setTransHtmlTitle] = useState('')
   const [transHtmlItems, setTransHtmlItems] = useState('')
   const [transHtmlItemTitle, setTransHtmlItemTitle] = useState('')
   const [transHtmlItemUrl, setTransHtmlItemUrl] = useState('')
   const [transHtmlItemUrlAttr, setTransHtmlItemUrlAttr] = useState('')
+  const [transHtmlItemGuid, setTransHtmlItemGuid] = useState('')
+  const [transHtmlItemGuidAttr, setTransHtmlItemGuidAttr] = useState('')
+  const [transHtmlItemGuidHash, setTransHtmlItemGuidHash] = useState('')
   const [transHtmlItemContent, setTransHtmlItemContent] = useState('')
+  const [transHtmlItemContentExclude, setTransHtmlItemContentExclude] = useState('')
+  const [transHtmlItemContentInner, setTransHtmlItemContentInner] = useState('')
+  const [transHtmlItemImage, setTransHtmlItemImage] = useState('')
+  const [transHtmlItemImageAttr, setTransHtmlItemImageAttr] = useState('')
   const [transHtmlItemDate, setTransHtmlItemDate] = useState('')
   const [transHtmlItemDateAttr, setTransHtmlItemDateAttr] = useState('')
+  const [transHtmlItemDateFormat, setTransHtmlItemDateFormat] = useState('')
+  const [transHtmlItemDateTimezone, setTransHtmlItemDateTimezone] = useState('')
+  const [transHtmlItemAuthor, setTransHtmlItemAuthor] = useState('')
+  const [transHtmlCharset, setTransHtmlCharset] = useState('')
+  const [transHtmlNextPage, setTransHtmlNextPage] = useState('')
+  const [transHtmlMaxPages, setTransHtmlMaxPages] = useState('')
+  const [transHtmlDetailContent, setTransHtmlDetailContent] = useState('')
+  const [transHtmlDetailDate, setTransHtmlDetailDate] = useState('')
+  const [transHtmlDetailDateAttr, setTransHtmlDetailDateAttr] = useState('')
+  const [transHtmlDetailImage, setTransHtmlDetailImage] = useState('')
+  const [transHtmlDetailImageAttr, setTransHtmlDetailImageAttr] = useState('')
   const transHtmlParams: Param[] = [
     {
       value: transHtmlUrl,
@@ -97,10 +119,47 @@ export default function FeedEditor({
           setUrl={setTransHtmlUrl}
           headers={transHtmlHeaders}
           setHeaders={setTransHtmlHeaders}
+          method={transHtmlMethod}
+          body={transHtmlBody}
+          contentType={transHtmlContentType}
         />
       ),
     },
     { value: transHtmlHeaders, setValue: setTransHtmlHeaders, key: 'headers', hide: true },
+    {
+      value: transHtmlMethod,
+      setValue: setTransHtmlMethod,
+      key: 'method',
+      desc: 'HTTP method of request',
+      placeholder: 'GET, or POST with body',
+    },
+    {
+      value: transHtmlBody,
+      setValue: setTransHtmlBody,
+      key: 'body',
+      desc: 'Body of request, e.g. GraphQL query',
+    },
+    {
+      value: transHtmlContentType,
+      setValue: setTransHtmlContentType,
+      key: 'content_type',
+      desc: (
+        <span>
+          Content type of body, e.g. <Code>application/json</Code>
+        </span>
+      ),
+    },
+    {
+      value: transHtmlSelectorType,
+      setValue: setTransHtmlSelectorType,
+      key: 'selector_type',
+      desc: (
+        <span>
+          <Code>css</Code> or <Code>xpath</Code>, syntax of selectors below
+        </span>
+      ),
+      placeholder: 'css',
+    },
     {
       value: transHtmlTitle,
       setValue: setTransHtmlTitle,
@@ -140,6 +199,37 @@ export default function FeedEditor({
       ),
       placeholder: 'href',
     },
+    {
+      value: transHtmlItemGuid,
+      setValue: setTransHtmlItemGuid,
+      key: 'item_guid',
+      desc: 'CSS selector targetting unique ID of item',
+      placeholder: 'URL of item',
+    },
+    {
+      value: transHtmlItemGuidAttr,
+      setValue: setTransHtmlItemGuidAttr,
+      key: 'item_guid_attr',
+      desc: (
+        <span>
+          Attribute of <Code>item_guid</Code> element as ID
+        </span>
+      ),
+      placeholder: 'element text',
+    },
+    {
+      value: transHtmlItemGuidHash,
+      setValue: setTransHtmlItemGuidHash,
+      key: 'item_guid_hash',
+      desc: (
+        <span>
+          Comma-separated fields (<Code>title</Code>, <Code>content</Code>, <Code>date</Code>)
+          hashed into ID of items without ID and URL, or <Code>none</Code>. Only add{' '}
+          <Code>date</Code> if dates are absolute
+        </span>
+      ),
+      placeholder: 'title,content',
+    },
     {
       value: transHtmlItemContent,
       setValue: setTransHtmlItemContent,
@@ -147,6 +237,42 @@ export default function FeedEditor({
       desc: 'CSS selector targetting content of item',
       placeholder: 'same as item element',
     },
+    {
+      value: transHtmlItemContentExclude,
+      setValue: setTransHtmlItemContentExclude,
+      key: 'item_content_exclude',
+      desc: 'CSS selectors, separated by commas, targetting elements removed from content',
+    },
+    {
+      value: transHtmlItemContentInner,
+      setValue: setTransHtmlItemContentInner,
+      key: 'item_content_inner',
+      desc: (
+        <span>
+          Set to <Code>true</Code> to use inner HTML of <Code>item_content</Code> element only
+        </span>
+      ),
+      placeholder: 'false',
+    },
+    {
+      value: transHtmlItemImage,
+      setValue: setTransHtmlItemImage,
+      key: 'item_image',
+      desc: 'CSS selector targetting image of item',
+      placeholder: 'first image in content',
+    },
+    {
+      value: transHtmlItemImageAttr,
+      setValue: setTransHtmlItemImageAttr,
+      key: 'item_image_attr',
+      desc: (
+        <span>
+          Attribute of <Code>item_image</Code> element as image URL, e.g. <Code>data-src</Code>,{' '}
+          <Code>srcset</Code> or <Code>style</Code> with background image
+        </span>
+      ),
+      placeholder: 'src',
+    },
     {
       value: transHtmlItemDate,
       setValue: setTransHtmlItemDate,
@@ -165,18 +291,131 @@ export default function FeedEditor({
       ),
       placeholder: 'element text',
     },
+    {
+      value: transHtmlItemDateFormat,
+      setValue: setTransHtmlItemDateFormat,
+      key: 'item_date_format',
+      desc: (
+        <span>
+          Format of publication date, as Go layout like <Code>2006-01-02 15:04</Code> or strftime
+          like <Code>%Y-%m-%d %H:%M</Code>
+        </span>
+      ),
+      placeholder: 'guessed',
+    },
+    {
+      value: transHtmlItemDateTimezone,
+      setValue: setTransHtmlItemDateTimezone,
+      key: 'item_date_timezone',
+      desc: 'Time zone of publication dates without one, e.g. Asia/Shanghai or +08:00',
+      placeholder: 'local time zone',
+    },
+    {
+      value: transHtmlItemAuthor,
+      setValue: setTransHtmlItemAuthor,
+      key: 'item_author',
+      desc: 'CSS selector targetting author of item',
+    },
+    {
+      value: transHtmlCharset,
+      setValue: setTransHtmlCharset,
+      key: 'charset',
+      desc: 'Character encoding of the page',
+      placeholder: 'detect from response',
+    },
+    {
+      value: transHtmlNextPage,
+      setValue: setTransHtmlNextPage,
+      key: 'next_page',
+      desc: 'CSS selector targetting link to next page',
+    },
+    {
+      value: transHtmlMaxPages,
+      setValue: setTransHtmlMaxPages,
+      key: 'max_pages',
+      desc: 'Maximum number of pages to fetch',
+      placeholder: '10',
+    },
+    {
+      value: transHtmlDetailContent,
+      setValue: setTransHtmlDetailContent,
+      key: 'detail_content',
+      desc: 'Selector targetting content on page of item',
+    },
+    {
+      value: transHtmlDetailDate,
+      setValue: setTransHtmlDetailDate,
+      key: 'detail_date_published',
+      desc: 'Selector targetting publication date on page of item',
+    },
+    {
+      value: transHtmlDetailDateAttr,
+      setValue: setTransHtmlDetailDateAttr,
+      key: 'detail_date_published_attr',
+      desc: (
+        <span>
+          Attribute of <Code>detail_date_published</Code> element as date
+        </span>
+      ),
+      placeholder: 'element text',
+    },
+    {
+      value: transHtmlDetailImage,
+      setValue: setTransHtmlDetailImage,
+      key: 'detail_image',
+      desc: (
+        <span>
+          Selector targetting image on page of item, e.g. <Code>img</Code> or{' '}
+          <Code>meta[property="og:image"]</Code>
+        </span>
+      ),
+    },
+    {
+      value: transHtmlDetailImageAttr,
+      setValue: setTransHtmlDetailImageAttr,
+      key: 'detail_image_attr',
+      desc: (
+        <span>
+          Attribute of <Code>detail_image</Code> element as image URL, e.g. <Code>data-src</Code>,{' '}
+          <Code>srcset</Code> or <Code>style</Code> with background image
+        </span>
+      ),
+      placeholder: 'src or content',
+    },
   ]
 
   const [transJsonUrl, setTransJsonUrl] = useState('')
+  const [transJsonEmbedded, setTransJsonEmbedded] = useState('')
+  const [transJsonEmbeddedRegex, setTransJsonEmbeddedRegex] = useState('')
   const [transJsonHomePageUrl, setTransJsonHomePageUrl] = useState('')
   const [transJsonTitle, setTransJsonTitle] = useState('')
   const [transJsonHeaders, setTransJsonHeaders] = useState('')
+  const [transJsonMethod, setTransJsonMethod] = useState('')
+  const [transJsonBody, setTransJsonBody] = useState('')
+  const [transJsonContentType, setTransJsonContentType] = useState('')
   const [transJsonItems, setTransJsonItems] = useState('')
   const [transJsonItemTitle, setTransJsonItemTitle] = useState('')
   const [transJsonItemUrl, setTransJsonItemUrl] = useState('')
   const [transJsonItemUrlPrefix, setTransJsonItemUrlPrefix] = useState('')
+  const [transJsonItemGuid, setTransJsonItemGuid] = useState('')
+  const [transJsonItemGuidHash, setTransJsonItemGuidHash] = useState('')
   const [transJsonItemContent, setTransJsonItemContent] = useState('')
+  const [transJsonItemContentExclude, setTransJsonItemContentExclude] = useState('')
+  const [transJsonItemImage, setTransJsonItemImage] = useState('')
   const [transJsonItemDate, setTransJsonItemDate] = useState('')
+  const [transJsonItemDateFormat, setTransJsonItemDateFormat] = useState('')
+  const [transJsonItemDateTimezone, setTransJsonItemDateTimezone] = useState('')
+  const [transJsonItemAuthor, setTransJsonItemAuthor] = useState('')
+  const [transJsonCharset, setTransJsonCharset] = useState('')
+  const [transJsonNextPage, setTransJsonNextPage] = useState('')
+  const [transJsonPageUrl, setTransJsonPageUrl] = useState('')
+  const [transJsonMaxPages, setTransJsonMaxPages] = useState('')
+  const [transJsonDetailContent, setTransJsonDetailContent] = useState('')
+  const [transJsonDetailDate, setTransJsonDetailDate] = useState('')
+  const [transJsonDetailDateAttr, setTransJsonDetailDateAttr] = useState('')
+  const [transJsonDetailImage, setTransJsonDetailImage] = useState('')
+  const [transJsonDetailImageAttr, setTransJsonDetailImageAttr] = useState('')
+  const [transJsonDetailSelectorType, setTransJsonDetailSelectorType] = useState('')
   const jsonPath = (
     <a
       style={{ color: 'inherit', textDecoration: 'underline' }}
@@ -200,9 +439,30 @@ export default function FeedEditor({
           setUrl={setTransJsonUrl}
           headers={transJsonHeaders}
           setHeaders={setTransJsonHeaders}
+          method={transJsonMethod}
+          body={transJsonBody}
+          contentType={transJsonContentType}
         />
       ),
     },
+    {
+      value: transJsonEmbedded,
+      setValue: setTransJsonEmbedded,
+      key: 'embedded_selector',
+      desc: (
+        <span>
+          CSS selector targetting element with JSON if URL is an HTML page, e.g.{' '}
+          <Code>#__NEXT_DATA__</Code> or <Code>script[type="application/ld+json"]</Code>
+        </span>
+      ),
+      placeholder: 'JSON response',
+    },
+    {
+      value: transJsonEmbeddedRegex,
+      setValue: setTransJsonEmbeddedRegex,
+      key: 'embedded_regex',
+      desc: 'Regular expression over HTML page whose first group is JSON, if no element matches',
+    },
     {
       value: transJsonHomePageUrl,
       setValue: setTransJsonHomePageUrl,
@@ -216,6 +476,29 @@ export default function FeedEditor({
       desc: <span>{jsonPath} to title of RSS</span>,
     },
     { value: transJsonHeaders, setValue: setTransJsonHeaders, key: 'headers', hide: true },
+    {
+      value: transJsonMethod,
+      setValue: setTransJsonMethod,
+      key: 'method',
+      desc: 'HTTP method of request',
+      placeholder: 'GET, or POST with body',
+    },
+    {
+      value: transJsonBody,
+      setValue: setTransJsonBody,
+      key: 'body',
+      desc: 'Body of request, e.g. GraphQL query',
+    },
+    {
+      value: transJsonContentType,
+      setValue: setTransJsonContentType,
+      key: 'content_type',
+      desc: (
+        <span>
+          Content type of body, e.g. <Code>application/json</Code>
+        </span>
+      ),
+    },
     {
       value: transJsonItems,
       setValue: setTransJsonItems,
@@ -241,23 +524,193 @@ export default function FeedEditor({
       key: 'item_url_prefix',
       desc: 'Optional prefix for URL',
     },
+    {
+      value: transJsonItemGuid,
+      setValue: setTransJsonItemGuid,
+      key: 'item_guid',
+      desc: <span>{jsonPath} to unique ID of item</span>,
+      placeholder: 'URL of item',
+    },
+    {
+      value: transJsonItemGuidHash,
+      setValue: setTransJsonItemGuidHash,
+      key: 'item_guid_hash',
+      desc: (
+        <span>
+          Comma-separated fields (<Code>title</Code>, <Code>content</Code>, <Code>date</Code>)
+          hashed into ID of items without ID and URL, or <Code>none</Code>. Only add{' '}
+          <Code>date</Code> if dates are absolute
+        </span>
+      ),
+      placeholder: 'title,content',
+    },
     {
       value: transJsonItemContent,
       setValue: setTransJsonItemContent,
       key: 'item_content',
       desc: <span>{jsonPath} to content of item</span>,
     },
+    {
+      value: transJsonItemContentExclude,
+      setValue: setTransJsonItemContentExclude,
+      key: 'item_content_exclude',
+      desc: 'CSS selectors, separated by commas, targetting elements removed from HTML content',
+    },
+    {
+      value: transJsonItemImage,
+      setValue: setTransJsonItemImage,
+      key: 'item_image',
+      desc: <span>{jsonPath} to image URL of item</span>,
+      placeholder: 'first image in content',
+    },
     {
       value: transJsonItemDate,
       setValue: setTransJsonItemDate,
       key: 'item_date_published',
       desc: <span>{jsonPath} to publication date of item</span>,
     },
+    {
+      value: transJsonItemDateFormat,
+      setValue: setTransJsonItemDateFormat,
+      key: 'item_date_format',
+      desc: (
+        <span>
+          Format of publication date, as Go layout like <Code>2006-01-02 15:04</Code> or strftime
+          like <Code>%Y-%m-%d %H:%M</Code>
+        </span>
+      ),
+      placeholder: 'guessed',
+    },
+    {
+      value: transJsonItemDateTimezone,
+      setValue: setTransJsonItemDateTimezone,
+      key: 'item_date_timezone',
+      desc: 'Time zone of publication dates without one, e.g. Asia/Shanghai or +08:00',
+      placeholder: 'local time zone',
+    },
+    {
+      value: transJsonItemAuthor,
+      setValue: setTransJsonItemAuthor,
+      key: 'item_author',
+      desc: <span>{jsonPath} to author of item</span>,
+    },
+    {
+      value: transJsonCharset,
+      setValue: setTransJsonCharset,
+      key: 'charset',
+      desc: 'Character encoding of the response',
+      placeholder: 'detect from response',
+    },
+    {
+      value: transJsonNextPage,
+      setValue: setTransJsonNextPage,
+      key: 'next_page',
+      desc: <span>{jsonPath} to URL of next page</span>,
+    },
+    {
+      value: transJsonPageUrl,
+      setValue: setTransJsonPageUrl,
+      key: 'page_url',
+      desc: (
+        <span>
+          URL of next pages, <Code>{'{page}'}</Code> is replaced with page number
+        </span>
+      ),
+      placeholder: 'https://example.com/api?page={page}',
+    },
+    {
+      value: transJsonMaxPages,
+      setValue: setTransJsonMaxPages,
+      key: 'max_pages',
+      desc: 'Maximum number of pages to fetch',
+      placeholder: '10',
+    },
+    {
+      value: transJsonDetailSelectorType,
+      setValue: setTransJsonDetailSelectorType,
+      key: 'detail_selector_type',
+      desc: (
+        <span>
+          <Code>css</Code> or <Code>xpath</Code>, syntax of selectors below
+        </span>
+      ),
+      placeholder: 'css',
+    },
+    {
+      value: transJsonDetailContent,
+      setValue: setTransJsonDetailContent,
+      key: 'detail_content',
+      desc: 'Selector targetting content on page of item',
+    },
+    {
+      value: transJsonDetailDate,
+      setValue: setTransJsonDetailDate,
+      key: 'detail_date_published',
+      desc: 'Selector targetting publication date on page of item',
+    },
+    {
+      value: transJsonDetailDateAttr,
+      setValue: setTransJsonDetailDateAttr,
+      key: 'detail_date_published_attr',
+      desc: (
+        <span>
+          Attribute of <Code>detail_date_published</Code> element as date
+        </span>
+      ),
+      placeholder: 'element text',
+    },
+    {
+      value: transJsonDetailImage,
+      setValue: setTransJsonDetailImage,
+      key: 'detail_image',
+      desc: (
+        <span>
+          Selector targetting image on page of item, e.g. <Code>img</Code> or{' '}
+          <Code>meta[property="og:image"]</Code>
+        </span>
+      ),
+    },
+    {
+      value: transJsonDetailImageAttr,
+      setValue: setTransJsonDetailImageAttr,
+      key: 'detail_image_attr',
+      desc: (
+        <span>
+          Attribute of <Code>detail_image</Code> element as image URL, e.g. <Code>data-src</Code>,{' '}
+          <Code>srcset</Code> or <Code>style</Code> with background image
+        </span>
+      ),
+      placeholder: 'src or content',
+    },
   ]
 
   const [js, setJs] = useState('')
+  const [jsTimeout, setJsTimeout] = useState('')
+  const [jsMemoryLimit, setJsMemoryLimit] = useState('')
+  const [jsMaxStackSize, setJsMaxStackSize] = useState('')
   const jsParams: Param[] = [
     { value: js, setValue: setJs, key: 'script', desc: 'JavaScript', script: true },
+    {
+      value: jsTimeout,
+      setValue: setJsTimeout,
+      key: 'timeout',
+      desc: 'Execution timeout in seconds',
+      placeholder: '30',
+    },
+    {
+      value: jsMemoryLimit,
+      setValue: setJsMemoryLimit,
+      key: 'memory_limit',
+      desc: 'Memory limit in MiB',
+      placeholder: '64',
+    },
+    {
+      value: jsMaxStackSize,
+      setValue: setJsMaxStackSize,
+      key: 'max_stack_size',
+      desc: 'Maximum stack size in KiB',
+      placeholder: '1024',
+    },
   ]
 
   useEffect(() => {
@@ -483,15 +936,26 @@ function FeedSection({
           </FormGroup>
         ))}
         {type !== 'feed' && (
-          <AnchorButton
-            text="Preview"
-            href={`api/transform/${type}${stringify(params)}`}
-            target="_blank"
-            intent={Intent.PRIMARY}
-            endIcon={<ExternalLink size={iconSize} />}
-            variant={ButtonVariant.OUTLINED}
-            fill
-          />
+          <div style={{ display: 'flex', gap: 8 }}>
+            <AnchorButton
+              text="Preview"
+              href={`api/transform/${type}${stringify(params)}`}
+              target="_blank"
+              intent={Intent.PRIMARY}
+              endIcon={<ExternalLink size={iconSize} />}
+              variant={ButtonVariant.OUTLINED}
+              fill
+            />
+            <AnchorButton
+              text="Debug"
+              href={`api/transform/${type}${stringify(params, { debug: 'true' })}`}
+              target="_blank"
+              title="Show requests, selector matches and console output"
+              endIcon={<ExternalLink size={iconSize} />}
+              variant={ButtonVariant.OUTLINED}
+              fill
+            />
+          </div>
         )}
       </SectionCard>
     </Section>
@@ -502,8 +966,9 @@ function Span(props: HTMLAttributes<HTMLSpanElement>) {
   return <span {...props} />
 }
 
-function stringify(params: Param[]) {
-  return param(
-    Object.fromEntries(params.filter(({ value }) => value).map(({ key, value }) => [key, value])),
-  )
+function stringify(params: Param[], extra?: Record<string, string>) {
+  return param({
+    ...Object.fromEntries(params.filter(({ value }) => value).map(({ key, value }) => [key, value])),
+    ...extra,
+  })
 }
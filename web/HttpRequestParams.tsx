@@ -16,11 +16,17 @@ export default function HttpRequestParams({
   setUrl,
   headers: rawHeaders,
   setHeaders,
+  method,
+  body,
+  contentType,
 }: {
   url: string
   setUrl: Dispatch<SetStateAction<string>>
   headers: string
   setHeaders: Dispatch<SetStateAction<string>>
+  method: string
+  body: string
+  contentType: string
 }) {
   const [newParamKey, setNewParamKey] = useState('')
   const newParamValue = useRef<HTMLInputElement>(null)
@@ -143,17 +149,47 @@ export default function HttpRequestParams({
           />
         </ControlGroup>
       </div>
-      {url && (
-        <AnchorButton
-          text="Preview"
-          style={{ marginTop: 8, userSelect: 'none' }}
-          href={`api/proxy${param({ url: rawUrl, headers: rawHeaders || undefined })}`}
-          target="_blank"
-          intent={Intent.PRIMARY}
-          endIcon={<ExternalLink size={iconSize} />}
-          variant={ButtonVariant.OUTLINED}
-        />
-      )}
+      {url &&
+        (/^(get|head)?$/i.test(method || (body ? 'POST' : '')) ? (
+          <AnchorButton
+            text="Preview"
+            style={{ marginTop: 8, userSelect: 'none' }}
+            href={`api/proxy${param({
+              url: rawUrl,
+              headers: rawHeaders || undefined,
+              method: method || undefined,
+            })}`}
+            target="_blank"
+            intent={Intent.PRIMARY}
+            endIcon={<ExternalLink size={iconSize} />}
+            variant={ButtonVariant.OUTLINED}
+          />
+        ) : (
+          // other methods are only proxied for JSON requests, which links cannot make
+          <Button
+            text="Preview"
+            style={{ marginTop: 8, userSelect: 'none' }}
+            intent={Intent.PRIMARY}
+            endIcon={<ExternalLink size={iconSize} />}
+            variant={ButtonVariant.OUTLINED}
+            onClick={async () => {
+              const preview = window.open()
+              if (!preview) return
+              const response = await fetch('api/proxy', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({
+                  url: rawUrl,
+                  headers: rawHeaders ? JSON.parse(rawHeaders) : undefined,
+                  method: method || undefined,
+                  body: body || undefined,
+                  content_type: contentType || undefined,
+                }),
+              })
+              preview.location = URL.createObjectURL(await response.blob())
+            }}
+          />
+        ))}
     </div>
   )
 }
@@ -18,8 +18,10 @@ import {
   Check,
   ChevronLeft,
   Circle,
+  Clock,
   Edit,
   ExternalLink,
+  FileText,
   Folder,
   Link,
   MoreHorizontal,
@@ -79,7 +81,9 @@ export default function ItemList() {
     if (searchValue) query.search = searchValue
     return query
   }
-  const updateFeedAttr = async <T extends 'title' | 'feed_link' | 'folder_id'>(
+  const updateFeedAttr = async <
+    T extends 'title' | 'feed_link' | 'folder_id' | 'fetch_content' | 'timezone',
+  >(
     id: number,
     attrName: T,
     value: Feed[T],
@@ -248,6 +252,21 @@ export default function ItemList() {
                         icon={<Edit size={iconSize} />}
                         onClick={() => setFeedLink(feed.feed_link)}
                       />
+                      <MenuItem
+                        text="Fetch Full Content"
+                        icon={<FileText size={iconSize} />}
+                        labelElement={feed.fetch_content && <Check size={iconSize} />}
+                        onClick={() =>
+                          updateFeedAttr(feed.id, 'fetch_content', !feed.fetch_content)
+                        }
+                      />
+                      <TextEditor
+                        menuText="Time Zone"
+                        menuIcon={<Clock size={iconSize} />}
+                        defaultValue={feed.timezone}
+                        placeholder="Time zone of dates without one, e.g. Asia/Shanghai or +08:00"
+                        onConfirm={timezone => updateFeedAttr(feed.id, 'timezone', timezone)}
+                      />
                       <MenuItem
                         text="Refresh"
                         icon={<RotateCw size={iconSize} />}
@@ -7,7 +7,8 @@ import {
   Divider,
   H2,
 } from '@blueprintjs/core'
-import { ChevronLeft, ChevronRight, Circle, ExternalLink, Star, X } from 'lucide-react'
+import { ChevronLeft, ChevronRight, Circle, ExternalLink, FileText, Star, X } from 'lucide-react'
+import { useState } from 'react'
 
 import { useMyContext } from './Context.tsx'
 import type { Item } from './types.ts'
@@ -26,6 +27,7 @@ export default function ItemShow() {
     selectItem,
     selectedItem: item,
   } = useMyContext()
+  const [extracting, setExtracting] = useState(false)
   if (!items || selectedItemId == null || !item) return undefined
 
   const toggleStatus = (target: Item['status']) => async () => {
@@ -91,6 +93,23 @@ export default function ItemShow() {
           rel="noopener noreferrer"
           referrerPolicy="no-referrer"
         />
+        <Button
+          icon={<FileText size={iconSize} />}
+          loading={extracting}
+          onClick={async () => {
+            setExtracting(true)
+            try {
+              const { content } = await xfetch<{ content: string }>(
+                `api/items/${item.id}/extract`,
+                { method: 'POST' },
+              )
+              setSelectedItem(i => (i && i.id === item.id ? { ...i, content } : i))
+            } finally {
+              setExtracting(false)
+            }
+          }}
+          title="Fetch Full Content"
+        />
         <div style={{ flexGrow: 1 }} />
         <Button
           icon={<ChevronLeft size={iconSize} />}
@@ -122,6 +141,14 @@ export default function ItemShow() {
         <div style={{ opacity: 0.95 }}>{feedsById?.get(item.feed_id)?.title}</div>
         <div style={{ opacity: 0.95 }}>{new Date(item.date).toLocaleString()}</div>
         <Divider compact style={{ marginBlock: 11 }} />
+        {item.podcast_url && (
+          <audio
+            controls
+            preload="none"
+            src={item.podcast_url}
+            style={{ width: '100%', marginBottom: 11 }}
+          />
+        )}
         <div
           style={{ fontSize: 16 }}
           className={cn(Classes.RUNNING_TEXT, 'content')}
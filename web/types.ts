@@ -15,6 +15,8 @@ export type Feed = {
   link?: string
   feed_link: string
   has_icon: boolean | null
+  fetch_content: boolean
+  timezone: string
 }
 
 export type FolderWithFeeds = Folder & { feeds: Feed[] }
@@ -25,13 +27,20 @@ export type Item = {
   feed_id: number
   title: string
   link: string
+  author?: string
   date: string
   status: 'unread' | 'read' | 'starred'
   image?: string
   podcast_url?: string
+  podcast_duration?: number
+  categories?: string[]
 }
 
-export type ItemWithContent = Item & { content: string }
+export type ItemWithContent = Item & {
+  content: string
+  podcast_chapters?: string
+  podcast_transcript?: string
+}
 
 export type Items = { list: Item[]; has_more: boolean }
 